@@ -0,0 +1,453 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// User roles, from least to most privileged. Coaches can change team data;
+// admins can also manage user accounts.
+const (
+	roleViewer = "viewer"
+	roleCoach  = "coach"
+	roleAdmin  = "admin"
+)
+
+var roleRank = map[string]int{
+	roleViewer: 1,
+	roleCoach:  2,
+	roleAdmin:  3,
+}
+
+const (
+	sessionLifetime   = 7 * 24 * time.Hour
+	minPasswordLength = 8
+	authUserKey       = "authUser"
+)
+
+// authUser is the signed-in user attached to a request.
+type authUser struct {
+	ID       int32
+	Username string
+	Role     string
+}
+
+type UserResponse struct {
+	ID        int32  `json:"id"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type LoginResponse struct {
+	Token     string       `json:"token"`
+	ExpiresAt string       `json:"expiresAt"`
+	User      UserResponse `json:"user"`
+}
+
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+	Role     string `json:"role" binding:"required,oneof=admin coach viewer"`
+}
+
+// UpdateUserRequest changes a user's role, password or both.
+type UpdateUserRequest struct {
+	Role     string `json:"role" binding:"omitempty,oneof=admin coach viewer"`
+	Password string `json:"password" binding:"omitempty,min=8"`
+}
+
+func newUserResponse(u db.User) UserResponse {
+	return UserResponse{
+		ID:        u.ID,
+		Username:  u.Username,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt.Time.Format(time.RFC3339),
+	}
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// hashToken is how session tokens are stored, so a leaked sessions table
+// cannot be used to sign in.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// createSession issues a new bearer token for a user.
+func createSession(ctx context.Context, userID int32) (string, time.Time, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(buf)
+	expires := time.Now().Add(sessionLifetime).UTC().Truncate(time.Second)
+
+	err := queries.CreateSession(ctx, db.CreateSessionParams{
+		TokenHash: hashToken(token),
+		UserID:    userID,
+		ExpiresAt: expires,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expires, nil
+}
+
+func bearerToken(c *gin.Context) string {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// authenticate attaches the user behind a bearer token to the request.
+// Requests without a token continue anonymously; a bad token is rejected.
+func authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := bearerToken(c)
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		session, err := queries.GetSessionUser(context.Background(), hashToken(token))
+		if err != nil && err != sql.ErrNoRows {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if err == sql.ErrNoRows || time.Now().After(session.ExpiresAt) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired session"})
+			return
+		}
+
+		c.Set(authUserKey, authUser{ID: session.ID, Username: session.Username, Role: session.Role})
+		c.Next()
+	}
+}
+
+func currentUser(c *gin.Context) (authUser, bool) {
+	v, ok := c.Get(authUserKey)
+	if !ok {
+		return authUser{}, false
+	}
+	user, ok := v.(authUser)
+	return user, ok
+}
+
+// requireRole only lets through users with at least the given role.
+func requireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+		if roleRank[user.Role] < roleRank[role] {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// protectWrites keeps reads public and requires a coach or admin for every
+// request that changes data, apart from signing in and out. Any signed-in
+// user may sign out, which the logout route checks itself.
+func protectWrites() gin.HandlerFunc {
+	requireCoach := requireRole(roleCoach)
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		switch c.FullPath() {
+		case "/api/auth/login", "/api/auth/logout":
+			c.Next()
+			return
+		}
+		requireCoach(c)
+	}
+}
+
+func registerAuthRoutes(r *gin.Engine) {
+	// Sign in and receive a bearer token
+	r.POST("/api/auth/login", func(c *gin.Context) {
+		var req LoginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		user, err := queries.GetUserByUsername(context.Background(), req.Username)
+		if err != nil && err != sql.ErrNoRows {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if err == sql.ErrNoRows || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+			return
+		}
+
+		if err := queries.DeleteExpiredSessions(context.Background(), time.Now().UTC()); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		token, expires, err := createSession(context.Background(), user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, LoginResponse{
+			Token:     token,
+			ExpiresAt: expires.Format(time.RFC3339),
+			User:      newUserResponse(user),
+		})
+	})
+
+	// Sign out, ending the current session
+	r.POST("/api/auth/logout", requireRole(roleViewer), func(c *gin.Context) {
+		err := queries.DeleteSession(context.Background(), hashToken(bearerToken(c)))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+	})
+
+	// Get the signed-in user
+	r.GET("/api/auth/me", requireRole(roleViewer), func(c *gin.Context) {
+		current, _ := currentUser(c)
+		user, err := queries.GetUserByID(context.Background(), current.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, newUserResponse(user))
+	})
+
+	users := r.Group("/api/users", requireRole(roleAdmin))
+
+	// Get all users
+	users.GET("", func(c *gin.Context) {
+		list, err := queries.GetAllUsers(context.Background())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]UserResponse, len(list))
+		for i, u := range list {
+			response[i] = newUserResponse(u)
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Create a user
+	users.POST("", func(c *gin.Context) {
+		var req CreateUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if _, err := queries.GetUserByUsername(context.Background(), req.Username); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Username is already taken"})
+			return
+		} else if err != sql.ErrNoRows {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		hash, err := hashPassword(req.Password)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		result, err := queries.CreateUser(context.Background(), db.CreateUserParams{
+			Username:     req.Username,
+			PasswordHash: hash,
+			Role:         req.Role,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		id, _ := result.LastInsertId()
+		c.JSON(http.StatusCreated, gin.H{"id": id, "message": "User created successfully"})
+	})
+
+	// Change a user's role or password; a new password signs them out
+	// everywhere
+	users.PUT("/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		var req UpdateUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if _, err := queries.GetUserByID(context.Background(), int32(id)); err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		current, _ := currentUser(c)
+		if req.Role != "" && req.Role != roleAdmin && current.ID == int32(id) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot remove your own admin role"})
+			return
+		}
+
+		tx, err := conn.BeginTx(context.Background(), nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback()
+		qtx := queries.WithTx(tx)
+
+		if req.Role != "" {
+			err := qtx.UpdateUserRole(context.Background(), db.UpdateUserRoleParams{Role: req.Role, ID: int32(id)})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if req.Password != "" {
+			hash, err := hashPassword(req.Password)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			err = qtx.UpdateUserPassword(context.Background(), db.UpdateUserPasswordParams{PasswordHash: hash, ID: int32(id)})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			if err := qtx.DeleteUserSessions(context.Background(), int32(id)); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "User updated successfully"})
+	})
+
+	// Delete a user
+	users.DELETE("/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		current, _ := currentUser(c)
+		if current.ID == int32(id) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot delete your own account"})
+			return
+		}
+
+		err = queries.DeleteUser(context.Background(), int32(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+	})
+}
+
+// runCreateAdminCommand implements the create-admin subcommand, which
+// bootstraps the first account:
+//
+//	server create-admin -username coach
+//
+// The password is read from ADMIN_PASSWORD, or from standard input if that is
+// not set.
+func runCreateAdminCommand(args []string) {
+	fs := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	username := fs.String("username", "", "username for the new admin")
+	fs.Parse(args)
+
+	if *username == "" {
+		fmt.Fprintln(os.Stderr, "usage: server create-admin -username NAME")
+		os.Exit(2)
+	}
+
+	password, ok := os.LookupEnv("ADMIN_PASSWORD")
+	if !ok {
+		fmt.Print("Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			log.Fatal("Failed to read password:", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+	if len(password) < minPasswordLength {
+		log.Fatalf("Password must be at least %d characters", minPasswordLength)
+	}
+
+	ctx := context.Background()
+	if _, err := queries.GetUserByUsername(ctx, *username); err == nil {
+		log.Fatalf("User %q already exists", *username)
+	} else if err != sql.ErrNoRows {
+		log.Fatal(err)
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, err = queries.CreateUser(ctx, db.CreateUserParams{
+		Username:     *username,
+		PasswordHash: hash,
+		Role:         roleAdmin,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Created admin %s\n", *username)
+}
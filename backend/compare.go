@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CompareAthlete is one side of a head-to-head comparison.
+type CompareAthlete struct {
+	ID       int32  `json:"id"`
+	Name     string `json:"name"`
+	Division string `json:"division"`
+	SchoolID int32  `json:"schoolId"`
+	School   string `json:"school"`
+	Wins     int    `json:"wins"`
+}
+
+type ComparedResult struct {
+	ResultID int32    `json:"resultId"`
+	Status   string   `json:"status"`
+	Time     RaceTime `json:"time"`
+	Place    int32    `json:"place"`
+}
+
+// ComparedRace is a race both athletes ran, with their results in the order
+// the athletes were asked for. Gap is how far the winner finished ahead and
+// is blank unless both finished; WinnerID is zero when neither did.
+type ComparedRace struct {
+	MeetID         int32            `json:"meetId"`
+	MeetName       string           `json:"meetName"`
+	MeetDate       string           `json:"meetDate"`
+	RaceID         int32            `json:"raceId"`
+	RaceName       string           `json:"raceName"`
+	DistanceMeters int32            `json:"distanceMeters"`
+	Results        []ComparedResult `json:"results"`
+	WinnerID       int32            `json:"winnerId"`
+	Gap            RaceTime         `json:"gap"`
+}
+
+// CompareResponse sums up two athletes' races against each other. AverageGap
+// is the mean margin over the races both finished, in favour of AheadID; it
+// is zero when they are dead even or never both finished.
+type CompareResponse struct {
+	Athletes   []CompareAthlete `json:"athletes"`
+	Races      []ComparedRace   `json:"races"`
+	Undecided  int              `json:"undecided"`
+	AverageGap RaceTime         `json:"averageGap"`
+	AheadID    int32            `json:"aheadId"`
+}
+
+// finishedAhead reports whether x beat y in a race they both ran. A finisher
+// beats a non-finisher; between finishers place decides, then time.
+func finishedAhead(x, y db.GetAthleteHistoryRow) bool {
+	xFinished, yFinished := x.Status == statusFinished, y.Status == statusFinished
+	switch {
+	case !xFinished:
+		return false
+	case !yFinished:
+		return true
+	case x.Place.Valid && y.Place.Valid && x.Place.Int32 != y.Place.Int32:
+		return x.Place.Int32 < y.Place.Int32
+	default:
+		return x.TimeMs.Int32 < y.TimeMs.Int32
+	}
+}
+
+// headToHead pairs up the races two athletes both ran, oldest first. A race
+// one of them did not start is not a meeting between them.
+func headToHead(aID int32, a []db.GetAthleteHistoryRow, bID int32, b []db.GetAthleteHistoryRow) []ComparedRace {
+	byRace := make(map[int32]db.GetAthleteHistoryRow, len(b))
+	for _, r := range b {
+		byRace[r.RaceID] = r
+	}
+
+	races := []ComparedRace{}
+	for _, ra := range a {
+		rb, ok := byRace[ra.RaceID]
+		if !ok || ra.Status == statusDNS || rb.Status == statusDNS {
+			continue
+		}
+		race := ComparedRace{
+			MeetID:         ra.MeetID,
+			MeetName:       ra.MeetName,
+			MeetDate:       ra.MeetDate.Format("2006-01-02"),
+			RaceID:         ra.RaceID,
+			RaceName:       ra.RaceName,
+			DistanceMeters: ra.DistanceMeters,
+			Results:        make([]ComparedResult, 2),
+		}
+		for i, r := range []db.GetAthleteHistoryRow{ra, rb} {
+			race.Results[i] = ComparedResult{
+				ResultID: r.ID,
+				Status:   r.Status,
+				Time:     RaceTime(r.TimeMs.Int32),
+				Place:    r.Place.Int32,
+			}
+		}
+		switch {
+		case finishedAhead(ra, rb):
+			race.WinnerID = aID
+		case finishedAhead(rb, ra):
+			race.WinnerID = bID
+		}
+		if ra.Status == statusFinished && rb.Status == statusFinished {
+			race.Gap = RaceTime(max(ra.TimeMs.Int32, rb.TimeMs.Int32) - min(ra.TimeMs.Int32, rb.TimeMs.Int32))
+		}
+		races = append(races, race)
+	}
+	return races
+}
+
+// seasonRows keeps the results from one season, or all of them when no
+// season is given.
+func seasonRows(rows []db.GetAthleteHistoryRow, seasonID sql.NullInt32) []db.GetAthleteHistoryRow {
+	if !seasonID.Valid {
+		return rows
+	}
+	var kept []db.GetAthleteHistoryRow
+	for _, r := range rows {
+		if r.SeasonID == seasonID {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// compareAthletesQuery reads the two athlete IDs in ?athletes=1,2.
+func compareAthletesQuery(c *gin.Context) ([]int32, error) {
+	parts := strings.Split(c.Query("athletes"), ",")
+	if len(parts) != 2 {
+		return nil, badRequest("Give two athletes, e.g. ?athletes=1,2")
+	}
+	ids := make([]int32, len(parts))
+	for i, p := range parts {
+		id, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return nil, badRequest("Invalid athlete ID")
+		}
+		ids[i] = int32(id)
+	}
+	if ids[0] == ids[1] {
+		return nil, badRequest("Give two different athletes")
+	}
+	return ids, nil
+}
+
+func registerCompareRoutes(r *gin.Engine) {
+	// Compare two athletes, ours or an opponent, over the races they both
+	// ran: ?athletes=1,2, optionally only within ?seasonId
+	r.GET("/api/compare", func(c *gin.Context) {
+		ids, err := compareAthletesQuery(c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		seasonID, err := idQuery(c, "seasonId", "season")
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		response := CompareResponse{Athletes: make([]CompareAthlete, len(ids))}
+		history := make([][]db.GetAthleteHistoryRow, len(ids))
+		for i, id := range ids {
+			athlete, err := queries.GetAthleteByID(context.Background(), id)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					c.JSON(http.StatusNotFound, gin.H{"error": "Athlete not found"})
+					return
+				}
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			school, err := queries.GetSchoolByID(context.Background(), athlete.SchoolID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			rows, err := queries.GetAthleteHistory(context.Background(), athlete.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+
+			response.Athletes[i] = CompareAthlete{
+				ID:       athlete.ID,
+				Name:     athlete.Name,
+				Division: athlete.Division,
+				SchoolID: school.ID,
+				School:   school.Name,
+			}
+			history[i] = seasonRows(rows, seasonID)
+		}
+
+		response.Races = headToHead(ids[0], history[0], ids[1], history[1])
+		var totalGap, bothFinished int64
+		for _, race := range response.Races {
+			switch race.WinnerID {
+			case ids[0]:
+				response.Athletes[0].Wins++
+			case ids[1]:
+				response.Athletes[1].Wins++
+			default:
+				response.Undecided++
+			}
+			if race.Results[0].Status == statusFinished && race.Results[1].Status == statusFinished {
+				totalGap += int64(race.Results[1].Time - race.Results[0].Time)
+				bothFinished++
+			}
+		}
+		if bothFinished > 0 {
+			average := RaceTime(totalGap / bothFinished)
+			switch {
+			case average > 0:
+				response.AheadID = ids[0]
+			case average < 0:
+				response.AheadID = ids[1]
+				average = -average
+			}
+			response.AverageGap = average
+		}
+		c.JSON(http.StatusOK, response)
+	})
+}
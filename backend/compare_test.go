@@ -0,0 +1,103 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"jones-county-xc/backend/db"
+)
+
+// historyRow is one of an athlete's results in race raceID. A zero time or
+// place leaves it blank, as for a non-finisher.
+func historyRow(raceID int32, status string, timeMs, place int32) db.GetAthleteHistoryRow {
+	return db.GetAthleteHistoryRow{
+		ID:             raceID*100 + place,
+		Status:         status,
+		TimeMs:         sql.NullInt32{Int32: timeMs, Valid: timeMs > 0},
+		Place:          sql.NullInt32{Int32: place, Valid: place > 0},
+		MeetID:         raceID,
+		MeetDate:       time.Date(2024, 9, 1+int(raceID), 0, 0, 0, 0, time.UTC),
+		RaceID:         raceID,
+		DistanceMeters: 5000,
+	}
+}
+
+func TestFinishedAhead(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y db.GetAthleteHistoryRow
+		want bool
+	}{
+		{"better place", historyRow(1, statusFinished, 1000000, 3), historyRow(1, statusFinished, 990000, 5), true},
+		{"worse place", historyRow(1, statusFinished, 990000, 5), historyRow(1, statusFinished, 1000000, 3), false},
+		{"no places, faster", historyRow(1, statusFinished, 990000, 0), historyRow(1, statusFinished, 1000000, 0), true},
+		{"same place, faster", historyRow(1, statusFinished, 990000, 4), historyRow(1, statusFinished, 1000000, 4), true},
+		{"finisher beats DNF", historyRow(1, statusFinished, 1200000, 90), historyRow(1, statusDNF, 0, 0), true},
+		{"DNF never ahead", historyRow(1, statusDNF, 0, 0), historyRow(1, statusFinished, 1200000, 90), false},
+		{"both DNF", historyRow(1, statusDNF, 0, 0), historyRow(1, statusDQ, 0, 0), false},
+	}
+	for _, tt := range tests {
+		if got := finishedAhead(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: finishedAhead = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHeadToHead(t *testing.T) {
+	a := []db.GetAthleteHistoryRow{
+		historyRow(1, statusFinished, 1000000, 3),
+		historyRow(2, statusFinished, 1010000, 8),
+		historyRow(3, statusDNS, 0, 0),
+		historyRow(4, statusDNF, 0, 0),
+		historyRow(5, statusFinished, 995000, 2),
+		historyRow(7, statusFinished, 990000, 1),
+	}
+	b := []db.GetAthleteHistoryRow{
+		historyRow(1, statusFinished, 1004500, 5),
+		historyRow(2, statusFinished, 1005000, 6),
+		historyRow(3, statusFinished, 1000000, 4),
+		historyRow(4, statusDNF, 0, 0),
+		historyRow(5, statusDNF, 0, 0),
+		historyRow(6, statusFinished, 1000000, 1),
+	}
+
+	races := headToHead(10, a, 20, b)
+
+	want := []struct {
+		raceID   int32
+		winnerID int32
+		gap      RaceTime
+	}{
+		{1, 10, 4500},
+		{2, 20, 5000},
+		{4, 0, 0},
+		{5, 10, 0},
+	}
+	if len(races) != len(want) {
+		t.Fatalf("got %d races, want %d: %+v", len(races), len(want), races)
+	}
+	for i, w := range want {
+		r := races[i]
+		if r.RaceID != w.raceID || r.WinnerID != w.winnerID || r.Gap != w.gap {
+			t.Errorf("race %d = {race %d winner %d gap %s}, want {race %d winner %d gap %s}",
+				i, r.RaceID, r.WinnerID, r.Gap, w.raceID, w.winnerID, w.gap)
+		}
+		if len(r.Results) != 2 || r.Results[0].ResultID != a[indexOfRace(a, r.RaceID)].ID || r.Results[1].ResultID != b[indexOfRace(b, r.RaceID)].ID {
+			t.Errorf("race %d results not in the order the athletes were given: %+v", r.RaceID, r.Results)
+		}
+	}
+
+	if got := headToHead(10, a, 20, nil); len(got) != 0 {
+		t.Errorf("no shared races gave %+v", got)
+	}
+}
+
+func indexOfRace(rows []db.GetAthleteHistoryRow, raceID int32) int {
+	for i, r := range rows {
+		if r.RaceID == raceID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseResponse struct {
+	ID             int32    `json:"id"`
+	Name           string   `json:"name"`
+	Venue          string   `json:"venue"`
+	DistanceMeters int32    `json:"distanceMeters"`
+	Surface        string   `json:"surface"`
+	Latitude       *float64 `json:"latitude"`
+	Longitude      *float64 `json:"longitude"`
+	Notes          string   `json:"notes"`
+}
+
+type CreateCourseRequest struct {
+	Name           string   `json:"name" binding:"required"`
+	Venue          string   `json:"venue" binding:"required"`
+	DistanceMeters int32    `json:"distanceMeters" binding:"required,gt=0"`
+	Surface        string   `json:"surface"`
+	Latitude       *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude      *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Notes          string   `json:"notes"`
+}
+
+type CourseResultResponse struct {
+	ID              int32    `json:"id"`
+	Time            RaceTime `json:"time"`
+	Place           int32    `json:"place"`
+	AthleteID       int32    `json:"athleteId"`
+	AthleteName     string   `json:"athleteName"`
+	AthleteGrade    int32    `json:"athleteGrade"`
+	AthleteDivision string   `json:"athleteDivision"`
+	MeetID          int32    `json:"meetId"`
+	MeetName        string   `json:"meetName"`
+	MeetDate        string   `json:"meetDate"`
+	RaceID          int32    `json:"raceId"`
+	RaceName        string   `json:"raceName"`
+	Pacing
+}
+
+// CourseRecordResponse is the fastest Jones County mark on a course for one
+// division at one distance.
+type CourseRecordResponse struct {
+	Division string               `json:"division"`
+	Record   CourseResultResponse `json:"record"`
+}
+
+func newCourseResponse(c db.Course) CourseResponse {
+	resp := CourseResponse{
+		ID:             c.ID,
+		Name:           c.Name,
+		Venue:          c.Venue,
+		DistanceMeters: c.DistanceMeters,
+		Surface:        c.Surface.String,
+		Notes:          c.Notes.String,
+	}
+	if c.Latitude.Valid {
+		resp.Latitude = &c.Latitude.Float64
+	}
+	if c.Longitude.Valid {
+		resp.Longitude = &c.Longitude.Float64
+	}
+	return resp
+}
+
+func nullFloat(f *float64) sql.NullFloat64 {
+	if f == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *f, Valid: true}
+}
+
+// nullID converts an optional ID, where zero means unset, for a nullable
+// foreign key column.
+func nullID(id int32) sql.NullInt32 {
+	return sql.NullInt32{Int32: id, Valid: id != 0}
+}
+
+// courseForMeet checks the course a meet is run on, where zero means none.
+func courseForMeet(ctx context.Context, courseID int32) (sql.NullInt32, error) {
+	if courseID == 0 {
+		return sql.NullInt32{}, nil
+	}
+	if _, err := queries.GetCourseByID(ctx, courseID); err != nil {
+		if err == sql.ErrNoRows {
+			return sql.NullInt32{}, badRequest("Course not found")
+		}
+		return sql.NullInt32{}, err
+	}
+	return nullID(courseID), nil
+}
+
+func newCourseResultResponse(r db.GetCourseResultsRow) CourseResultResponse {
+	return CourseResultResponse{
+		ID:              r.ID,
+		Time:            RaceTime(r.TimeMs.Int32),
+		Place:           r.Place.Int32,
+		AthleteID:       r.AthleteID,
+		AthleteName:     r.AthleteName,
+		AthleteGrade:    gradeAt(r.AthleteGrade, r.SeasonGrade),
+		AthleteDivision: r.AthleteDivision,
+		MeetID:          r.MeetID,
+		MeetName:        r.MeetName,
+		MeetDate:        r.MeetDate.Format("2006-01-02"),
+		RaceID:          r.RaceID,
+		RaceName:        r.RaceName,
+		Pacing:          newPacing(RaceTime(r.TimeMs.Int32), r.DistanceMeters),
+	}
+}
+
+// courseRecords picks the fastest result for every division and distance run
+// on a course.
+func courseRecords(results []db.GetCourseResultsRow) []CourseRecordResponse {
+	type key struct {
+		division string
+		distance int32
+	}
+	best := make(map[key]db.GetCourseResultsRow)
+	for _, r := range results {
+		k := key{r.AthleteDivision, r.DistanceMeters}
+		if b, ok := best[k]; !ok || r.TimeMs.Int32 < b.TimeMs.Int32 {
+			best[k] = r
+		}
+	}
+
+	records := make([]CourseRecordResponse, 0, len(best))
+	for k, r := range best {
+		records = append(records, CourseRecordResponse{
+			Division: k.division,
+			Record:   newCourseResultResponse(r),
+		})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Division != records[j].Division {
+			return records[i].Division < records[j].Division
+		}
+		return records[i].Record.DistanceMeters < records[j].Record.DistanceMeters
+	})
+	return records
+}
+
+func registerCourseRoutes(r *gin.Engine) {
+	// Get all courses
+	r.GET("/api/courses", func(c *gin.Context) {
+		courses, err := queries.GetAllCourses(context.Background())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]CourseResponse, len(courses))
+		for i, course := range courses {
+			response[i] = newCourseResponse(course)
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Get course by ID
+	r.GET("/api/courses/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+			return
+		}
+
+		course, err := queries.GetCourseByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, newCourseResponse(course))
+	})
+
+	// Create a new course
+	r.POST("/api/courses", func(c *gin.Context) {
+		var req CreateCourseRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := queries.CreateCourse(context.Background(), db.CreateCourseParams{
+			Name:           req.Name,
+			Venue:          req.Venue,
+			DistanceMeters: req.DistanceMeters,
+			Surface:        sql.NullString{String: req.Surface, Valid: req.Surface != ""},
+			Latitude:       nullFloat(req.Latitude),
+			Longitude:      nullFloat(req.Longitude),
+			Notes:          sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		id, _ := result.LastInsertId()
+		c.JSON(http.StatusCreated, gin.H{"id": id, "message": "Course created successfully"})
+	})
+
+	// Update a course
+	r.PUT("/api/courses/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+			return
+		}
+
+		var req CreateCourseRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = queries.UpdateCourse(context.Background(), db.UpdateCourseParams{
+			ID:             int32(id),
+			Name:           req.Name,
+			Venue:          req.Venue,
+			DistanceMeters: req.DistanceMeters,
+			Surface:        sql.NullString{String: req.Surface, Valid: req.Surface != ""},
+			Latitude:       nullFloat(req.Latitude),
+			Longitude:      nullFloat(req.Longitude),
+			Notes:          sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Course updated successfully"})
+	})
+
+	// Delete a course; its meets are kept but unlinked
+	r.DELETE("/api/courses/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+			return
+		}
+
+		err = queries.DeleteCourse(context.Background(), int32(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Course deleted successfully"})
+	})
+
+	// Get every result the team has ever run on a course (the home school's
+	// unless ?schoolId is given)
+	r.GET("/api/courses/:id/results", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+			return
+		}
+
+		schoolID, err := schoolQuery(context.Background(), c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		results, err := queries.GetCourseResults(context.Background(), db.GetCourseResultsParams{
+			CourseID: nullID(int32(id)),
+			SchoolID: nullID(schoolID),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]CourseResultResponse, len(results))
+		for i, r := range results {
+			response[i] = newCourseResultResponse(r)
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Get the team's course records per division (the home school's unless
+	// ?schoolId is given)
+	r.GET("/api/courses/:id/records", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+			return
+		}
+
+		schoolID, err := schoolQuery(context.Background(), c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		results, err := queries.GetCourseResults(context.Background(), db.GetCourseResultsParams{
+			CourseID: nullID(int32(id)),
+			SchoolID: nullID(schoolID),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, courseRecords(results))
+	})
+
+	// Get the team's all-time best times on a course per division, at the
+	// course distance unless another is given. The home school's are listed
+	// unless ?schoolId is given.
+	r.GET("/api/courses/:id/best-times", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+			return
+		}
+
+		course, err := queries.GetCourseByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		distance, err := strconv.Atoi(c.DefaultQuery("distance", strconv.Itoa(int(course.DistanceMeters))))
+		if err != nil || distance <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid distance"})
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit, use 1 to " + strconv.Itoa(maxLeaderboardLimit)})
+			return
+		}
+
+		schoolID, err := schoolQuery(context.Background(), c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		selected, err := divisionsFromQuery(c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]DivisionTopTimesResponse, len(selected))
+		for d, division := range selected {
+			times, err := queries.GetCourseBestTimes(context.Background(), db.GetCourseBestTimesParams{
+				CourseID:       nullID(course.ID),
+				DistanceMeters: int32(distance),
+				Division:       division,
+				SchoolID:       schoolID,
+				Limit:          int32(limit),
+			})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+
+			list := make([]TopTimeResponse, len(times))
+			for i, t := range times {
+				list[i] = TopTimeResponse{
+					ID:          t.ID,
+					Time:        RaceTime(t.TimeMs.Int32),
+					Place:       t.Place.Int32,
+					AthleteID:   t.AthleteID,
+					AthleteName: t.AthleteName,
+					MeetID:      t.MeetID,
+					MeetName:    t.MeetName,
+					MeetDate:    t.MeetDate.Format("2006-01-02"),
+					RaceID:      t.RaceID,
+					RaceName:    t.RaceName,
+					Pacing:      newPacing(RaceTime(t.TimeMs.Int32), int32(distance)),
+				}
+			}
+			rankTimes(list)
+			response[d] = DivisionTopTimesResponse{Division: division, Times: list}
+		}
+		c.JSON(http.StatusOK, response)
+	})
+}
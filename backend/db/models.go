@@ -10,28 +10,141 @@ import (
 )
 
 type Athlete struct {
+	ID        int32
+	Name      string
+	Grade     int32
+	Division  string
+	SchoolID  int32
+	Status    string
+	Events    sql.NullString
+	CreatedAt sql.NullTime
+}
+
+type Course struct {
 	ID             int32
 	Name           string
-	Grade          int32
-	PersonalRecord sql.NullString
-	Events         sql.NullString
+	Venue          string
+	DistanceMeters int32
+	Surface        sql.NullString
+	Latitude       sql.NullFloat64
+	Longitude      sql.NullFloat64
+	Notes          sql.NullString
 	CreatedAt      sql.NullTime
 }
 
+type Entry struct {
+	ID        int32
+	MeetID    int32
+	RaceID    int32
+	AthleteID int32
+	Bib       sql.NullInt32
+	CreatedAt sql.NullTime
+}
+
 type Meet struct {
 	ID          int32
 	Name        string
 	Date        time.Time
 	Location    string
+	CourseID    sql.NullInt32
+	SeasonID    sql.NullInt32
 	Description sql.NullString
 	CreatedAt   sql.NullTime
 }
 
+type Race struct {
+	ID             int32
+	MeetID         int32
+	Name           string
+	DistanceMeters int32
+	EntryLimit     sql.NullInt32
+	StartTime      sql.NullTime
+	CreatedAt      sql.NullTime
+}
+
 type Result struct {
 	ID        int32
 	AthleteID int32
 	MeetID    int32
-	Time      string
-	Place     int32
+	RaceID    int32
+	Status    string
+	TimeMs    sql.NullInt32
+	Place     sql.NullInt32
+	CreatedAt sql.NullTime
+}
+
+type School struct {
+	ID        int32
+	Name      string
+	IsHome    bool
+	CreatedAt sql.NullTime
+}
+
+type Season struct {
+	ID        int32
+	Name      string
+	Year      int32
+	StartDate time.Time
+	EndDate   time.Time
 	CreatedAt sql.NullTime
 }
+
+type SeasonRoster struct {
+	SeasonID  int32
+	AthleteID int32
+	Grade     int32
+	CreatedAt sql.NullTime
+}
+
+type Session struct {
+	TokenHash string
+	UserID    int32
+	ExpiresAt time.Time
+	CreatedAt sql.NullTime
+}
+
+type Split struct {
+	ID             int32
+	ResultID       int32
+	DistanceMeters int32
+	ElapsedMs      int32
+	CreatedAt      sql.NullTime
+}
+
+type TimingSession struct {
+	ID        int32
+	MeetID    int32
+	RaceID    int32
+	Status    string
+	StartedAt time.Time
+	CreatedAt sql.NullTime
+}
+
+type TimingTap struct {
+	ID         int32
+	SessionID  int32
+	ElapsedMs  int32
+	AthleteID  sql.NullInt32
+	RecordedAt time.Time
+	CreatedAt  sql.NullTime
+}
+
+type User struct {
+	ID           int32
+	Username     string
+	PasswordHash string
+	Role         string
+	CreatedAt    sql.NullTime
+}
+
+type Workout struct {
+	ID             int32
+	AthleteID      int32
+	Date           time.Time
+	Type           string
+	DistanceMeters int32
+	DurationMs     sql.NullInt32
+	Effort         sql.NullInt32
+	Notes          sql.NullString
+	CreatedAt      sql.NullTime
+}
@@ -8,39 +8,295 @@ package db
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 )
 
+const addRosterMember = `-- name: AddRosterMember :exec
+INSERT INTO season_rosters (season_id, athlete_id, grade)
+VALUES (?, ?, ?)
+`
+
+type AddRosterMemberParams struct {
+	SeasonID  int32
+	AthleteID int32
+	Grade     int32
+}
+
+func (q *Queries) AddRosterMember(ctx context.Context, arg AddRosterMemberParams) error {
+	_, err := q.db.ExecContext(ctx, addRosterMember,
+		arg.SeasonID,
+		arg.AthleteID,
+		arg.Grade,
+	)
+	return err
+}
+
+const addRosterMemberIfMissing = `-- name: AddRosterMemberIfMissing :exec
+INSERT IGNORE INTO season_rosters (season_id, athlete_id, grade)
+VALUES (?, ?, ?)
+`
+
+type AddRosterMemberIfMissingParams struct {
+	SeasonID  int32
+	AthleteID int32
+	Grade     int32
+}
+
+func (q *Queries) AddRosterMemberIfMissing(ctx context.Context, arg AddRosterMemberIfMissingParams) error {
+	_, err := q.db.ExecContext(ctx, addRosterMemberIfMissing,
+		arg.SeasonID,
+		arg.AthleteID,
+		arg.Grade,
+	)
+	return err
+}
+
+const assignTimingTap = `-- name: AssignTimingTap :exec
+UPDATE timing_taps SET athlete_id = ? WHERE id = ? AND session_id = ?
+`
+
+type AssignTimingTapParams struct {
+	AthleteID sql.NullInt32
+	ID        int32
+	SessionID int32
+}
+
+func (q *Queries) AssignTimingTap(ctx context.Context, arg AssignTimingTapParams) error {
+	_, err := q.db.ExecContext(ctx, assignTimingTap,
+		arg.AthleteID,
+		arg.ID,
+		arg.SessionID,
+	)
+	return err
+}
+
+const countAthletes = `-- name: CountAthletes :one
+SELECT COUNT(*)
+FROM athletes
+WHERE school_id = ?
+  AND grade >= ? AND grade <= ?
+  AND division LIKE ? AND status LIKE ?
+  AND name LIKE ?
+`
+
+type CountAthletesParams struct {
+	SchoolID int32
+	MinGrade int32
+	MaxGrade int32
+	Division string
+	Status   string
+	Name     string
+}
+
+func (q *Queries) CountAthletes(ctx context.Context, arg CountAthletesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAthletes,
+		arg.SchoolID,
+		arg.MinGrade,
+		arg.MaxGrade,
+		arg.Division,
+		arg.Status,
+		arg.Name,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countMeetResults = `-- name: CountMeetResults :one
+SELECT COUNT(*)
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+WHERE r.meet_id = ?
+  AND r.race_id >= ? AND r.race_id <= ?
+  AND a.division LIKE ? AND a.name LIKE ?
+`
+
+type CountMeetResultsParams struct {
+	MeetID    int32
+	MinRaceID int32
+	MaxRaceID int32
+	Division  string
+	Name      string
+}
+
+func (q *Queries) CountMeetResults(ctx context.Context, arg CountMeetResultsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countMeetResults,
+		arg.MeetID,
+		arg.MinRaceID,
+		arg.MaxRaceID,
+		arg.Division,
+		arg.Name,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countMeets = `-- name: CountMeets :one
+SELECT COUNT(*)
+FROM meets
+WHERE date >= ? AND date <= ?
+  AND location LIKE ? AND name LIKE ?
+`
+
+type CountMeetsParams struct {
+	FromDate time.Time
+	ToDate   time.Time
+	Location string
+	Name     string
+}
+
+func (q *Queries) CountMeets(ctx context.Context, arg CountMeetsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countMeets,
+		arg.FromDate,
+		arg.ToDate,
+		arg.Location,
+		arg.Name,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOtherRaceEntries = `-- name: CountOtherRaceEntries :one
+SELECT COUNT(*)
+FROM entries
+WHERE race_id = ? AND athlete_id <> ?
+`
+
+type CountOtherRaceEntriesParams struct {
+	RaceID    int32
+	AthleteID int32
+}
+
+func (q *Queries) CountOtherRaceEntries(ctx context.Context, arg CountOtherRaceEntriesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOtherRaceEntries,
+		arg.RaceID,
+		arg.AthleteID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countRunningTimingSessions = `-- name: CountRunningTimingSessions :one
+SELECT COUNT(*)
+FROM timing_sessions
+WHERE race_id = ? AND status = 'running'
+`
+
+func (q *Queries) CountRunningTimingSessions(ctx context.Context, raceID int32) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRunningTimingSessions, raceID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSchoolAthletes = `-- name: CountSchoolAthletes :one
+SELECT COUNT(*) FROM athletes WHERE school_id = ?
+`
+
+func (q *Queries) CountSchoolAthletes(ctx context.Context, schoolID int32) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSchoolAthletes, schoolID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSeasonRoster = `-- name: CountSeasonRoster :one
+SELECT COUNT(*) FROM season_rosters WHERE season_id = ?
+`
+
+func (q *Queries) CountSeasonRoster(ctx context.Context, seasonID int32) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSeasonRoster, seasonID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAthlete = `-- name: CreateAthlete :execresult
-INSERT INTO athletes (name, grade, personal_record, events)
-VALUES (?, ?, ?, ?)
+INSERT INTO athletes (name, grade, division, school_id, events)
+VALUES (?, ?, ?, ?, ?)
 `
 
 type CreateAthleteParams struct {
-	Name           string
-	Grade          int32
-	PersonalRecord sql.NullString
-	Events         sql.NullString
+	Name     string
+	Grade    int32
+	Division string
+	SchoolID int32
+	Events   sql.NullString
 }
 
 func (q *Queries) CreateAthlete(ctx context.Context, arg CreateAthleteParams) (sql.Result, error) {
 	return q.db.ExecContext(ctx, createAthlete,
 		arg.Name,
 		arg.Grade,
-		arg.PersonalRecord,
+		arg.Division,
+		arg.SchoolID,
 		arg.Events,
 	)
 }
 
-const createMeet = `-- name: CreateMeet :execresult
-INSERT INTO meets (name, date, location, description)
+const createCourse = `-- name: CreateCourse :execresult
+INSERT INTO courses (name, venue, distance_meters, surface, latitude, longitude, notes)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateCourseParams struct {
+	Name           string
+	Venue          string
+	DistanceMeters int32
+	Surface        sql.NullString
+	Latitude       sql.NullFloat64
+	Longitude      sql.NullFloat64
+	Notes          sql.NullString
+}
+
+func (q *Queries) CreateCourse(ctx context.Context, arg CreateCourseParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createCourse,
+		arg.Name,
+		arg.Venue,
+		arg.DistanceMeters,
+		arg.Surface,
+		arg.Latitude,
+		arg.Longitude,
+		arg.Notes,
+	)
+}
+
+const createEntry = `-- name: CreateEntry :execresult
+INSERT INTO entries (meet_id, race_id, athlete_id, bib)
 VALUES (?, ?, ?, ?)
 `
 
+type CreateEntryParams struct {
+	MeetID    int32
+	RaceID    int32
+	AthleteID int32
+	Bib       sql.NullInt32
+}
+
+func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createEntry,
+		arg.MeetID,
+		arg.RaceID,
+		arg.AthleteID,
+		arg.Bib,
+	)
+}
+
+const createMeet = `-- name: CreateMeet :execresult
+INSERT INTO meets (name, date, location, course_id, season_id, description)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
 type CreateMeetParams struct {
 	Name        string
 	Date        time.Time
 	Location    string
+	CourseID    sql.NullInt32
+	SeasonID    sql.NullInt32
 	Description sql.NullString
 }
 
@@ -49,31 +305,212 @@ func (q *Queries) CreateMeet(ctx context.Context, arg CreateMeetParams) (sql.Res
 		arg.Name,
 		arg.Date,
 		arg.Location,
+		arg.CourseID,
+		arg.SeasonID,
 		arg.Description,
 	)
 }
 
+const createRace = `-- name: CreateRace :execresult
+INSERT INTO races (meet_id, name, distance_meters, entry_limit, start_time)
+VALUES (?, ?, ?, ?, ?)
+`
+
+type CreateRaceParams struct {
+	MeetID         int32
+	Name           string
+	DistanceMeters int32
+	EntryLimit     sql.NullInt32
+	StartTime      sql.NullTime
+}
+
+func (q *Queries) CreateRace(ctx context.Context, arg CreateRaceParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createRace,
+		arg.MeetID,
+		arg.Name,
+		arg.DistanceMeters,
+		arg.EntryLimit,
+		arg.StartTime,
+	)
+}
+
 const createResult = `-- name: CreateResult :execresult
-INSERT INTO results (athlete_id, meet_id, time, place)
-VALUES (?, ?, ?, ?)
+INSERT INTO results (athlete_id, meet_id, race_id, status, time_ms, place)
+VALUES (?, ?, ?, ?, ?, ?)
 `
 
 type CreateResultParams struct {
 	AthleteID int32
 	MeetID    int32
-	Time      string
-	Place     int32
+	RaceID    int32
+	Status    string
+	TimeMs    sql.NullInt32
+	Place     sql.NullInt32
 }
 
 func (q *Queries) CreateResult(ctx context.Context, arg CreateResultParams) (sql.Result, error) {
 	return q.db.ExecContext(ctx, createResult,
 		arg.AthleteID,
 		arg.MeetID,
-		arg.Time,
+		arg.RaceID,
+		arg.Status,
+		arg.TimeMs,
 		arg.Place,
 	)
 }
 
+const createSchool = `-- name: CreateSchool :execresult
+INSERT INTO schools (name)
+VALUES (?)
+`
+
+func (q *Queries) CreateSchool(ctx context.Context, name string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createSchool, name)
+}
+
+const createSeason = `-- name: CreateSeason :execresult
+INSERT INTO seasons (name, year, start_date, end_date)
+VALUES (?, ?, ?, ?)
+`
+
+type CreateSeasonParams struct {
+	Name      string
+	Year      int32
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+func (q *Queries) CreateSeason(ctx context.Context, arg CreateSeasonParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createSeason,
+		arg.Name,
+		arg.Year,
+		arg.StartDate,
+		arg.EndDate,
+	)
+}
+
+const createSession = `-- name: CreateSession :exec
+INSERT INTO sessions (token_hash, user_id, expires_at) VALUES (?, ?, ?)
+`
+
+type CreateSessionParams struct {
+	TokenHash string
+	UserID    int32
+	ExpiresAt time.Time
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) error {
+	_, err := q.db.ExecContext(ctx, createSession,
+		arg.TokenHash,
+		arg.UserID,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createSplit = `-- name: CreateSplit :exec
+INSERT INTO splits (result_id, distance_meters, elapsed_ms)
+VALUES (?, ?, ?)
+`
+
+type CreateSplitParams struct {
+	ResultID       int32
+	DistanceMeters int32
+	ElapsedMs      int32
+}
+
+func (q *Queries) CreateSplit(ctx context.Context, arg CreateSplitParams) error {
+	_, err := q.db.ExecContext(ctx, createSplit,
+		arg.ResultID,
+		arg.DistanceMeters,
+		arg.ElapsedMs,
+	)
+	return err
+}
+
+const createTimingSession = `-- name: CreateTimingSession :execresult
+INSERT INTO timing_sessions (meet_id, race_id, started_at)
+VALUES (?, ?, ?)
+`
+
+type CreateTimingSessionParams struct {
+	MeetID    int32
+	RaceID    int32
+	StartedAt time.Time
+}
+
+func (q *Queries) CreateTimingSession(ctx context.Context, arg CreateTimingSessionParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createTimingSession,
+		arg.MeetID,
+		arg.RaceID,
+		arg.StartedAt,
+	)
+}
+
+const createTimingTap = `-- name: CreateTimingTap :execresult
+INSERT INTO timing_taps (session_id, elapsed_ms, recorded_at)
+VALUES (?, ?, ?)
+`
+
+type CreateTimingTapParams struct {
+	SessionID  int32
+	ElapsedMs  int32
+	RecordedAt time.Time
+}
+
+func (q *Queries) CreateTimingTap(ctx context.Context, arg CreateTimingTapParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createTimingTap,
+		arg.SessionID,
+		arg.ElapsedMs,
+		arg.RecordedAt,
+	)
+}
+
+const createUser = `-- name: CreateUser :execresult
+INSERT INTO users (username, password_hash, role) VALUES (?, ?, ?)
+`
+
+type CreateUserParams struct {
+	Username     string
+	PasswordHash string
+	Role         string
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createUser,
+		arg.Username,
+		arg.PasswordHash,
+		arg.Role,
+	)
+}
+
+const createWorkout = `-- name: CreateWorkout :execresult
+INSERT INTO workouts (athlete_id, date, type, distance_meters, duration_ms, effort, notes)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateWorkoutParams struct {
+	AthleteID      int32
+	Date           time.Time
+	Type           string
+	DistanceMeters int32
+	DurationMs     sql.NullInt32
+	Effort         sql.NullInt32
+	Notes          sql.NullString
+}
+
+func (q *Queries) CreateWorkout(ctx context.Context, arg CreateWorkoutParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createWorkout,
+		arg.AthleteID,
+		arg.Date,
+		arg.Type,
+		arg.DistanceMeters,
+		arg.DurationMs,
+		arg.Effort,
+		arg.Notes,
+	)
+}
+
 const deleteAthlete = `-- name: DeleteAthlete :exec
 DELETE FROM athletes WHERE id = ?
 `
@@ -83,6 +520,41 @@ func (q *Queries) DeleteAthlete(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteCourse = `-- name: DeleteCourse :exec
+DELETE FROM courses WHERE id = ?
+`
+
+func (q *Queries) DeleteCourse(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteCourse, id)
+	return err
+}
+
+const deleteEntry = `-- name: DeleteEntry :exec
+DELETE FROM entries WHERE meet_id = ? AND athlete_id = ?
+`
+
+type DeleteEntryParams struct {
+	MeetID    int32
+	AthleteID int32
+}
+
+func (q *Queries) DeleteEntry(ctx context.Context, arg DeleteEntryParams) error {
+	_, err := q.db.ExecContext(ctx, deleteEntry,
+		arg.MeetID,
+		arg.AthleteID,
+	)
+	return err
+}
+
+const deleteExpiredSessions = `-- name: DeleteExpiredSessions :exec
+DELETE FROM sessions WHERE expires_at < ?
+`
+
+func (q *Queries) DeleteExpiredSessions(ctx context.Context, expiresAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredSessions, expiresAt)
+	return err
+}
+
 const deleteMeet = `-- name: DeleteMeet :exec
 DELETE FROM meets WHERE id = ?
 `
@@ -92,6 +564,24 @@ func (q *Queries) DeleteMeet(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteMeetEntries = `-- name: DeleteMeetEntries :exec
+DELETE FROM entries WHERE meet_id = ?
+`
+
+func (q *Queries) DeleteMeetEntries(ctx context.Context, meetID int32) error {
+	_, err := q.db.ExecContext(ctx, deleteMeetEntries, meetID)
+	return err
+}
+
+const deleteRace = `-- name: DeleteRace :exec
+DELETE FROM races WHERE id = ?
+`
+
+func (q *Queries) DeleteRace(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteRace, id)
+	return err
+}
+
 const deleteResult = `-- name: DeleteResult :exec
 DELETE FROM results WHERE id = ?
 `
@@ -101,28 +591,1823 @@ func (q *Queries) DeleteResult(ctx context.Context, id int32) error {
 	return err
 }
 
-const getAllAthletes = `-- name: GetAllAthletes :many
-SELECT id, name, grade, personal_record, events, created_at
-FROM athletes
-ORDER BY name
+const deleteResultSplits = `-- name: DeleteResultSplits :exec
+DELETE FROM splits WHERE result_id = ?
 `
 
-func (q *Queries) GetAllAthletes(ctx context.Context) ([]Athlete, error) {
-	rows, err := q.db.QueryContext(ctx, getAllAthletes)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Athlete
-	for rows.Next() {
-		var i Athlete
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Grade,
-			&i.PersonalRecord,
-			&i.Events,
-			&i.CreatedAt,
+func (q *Queries) DeleteResultSplits(ctx context.Context, resultID int32) error {
+	_, err := q.db.ExecContext(ctx, deleteResultSplits, resultID)
+	return err
+}
+
+const deleteSchool = `-- name: DeleteSchool :exec
+DELETE FROM schools WHERE id = ?
+`
+
+func (q *Queries) DeleteSchool(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteSchool, id)
+	return err
+}
+
+const deleteSeason = `-- name: DeleteSeason :exec
+DELETE FROM seasons WHERE id = ?
+`
+
+func (q *Queries) DeleteSeason(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteSeason, id)
+	return err
+}
+
+const deleteSession = `-- name: DeleteSession :exec
+DELETE FROM sessions WHERE token_hash = ?
+`
+
+func (q *Queries) DeleteSession(ctx context.Context, tokenHash string) error {
+	_, err := q.db.ExecContext(ctx, deleteSession, tokenHash)
+	return err
+}
+
+const deleteTimingSession = `-- name: DeleteTimingSession :exec
+DELETE FROM timing_sessions WHERE id = ?
+`
+
+func (q *Queries) DeleteTimingSession(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteTimingSession, id)
+	return err
+}
+
+const deleteTimingTap = `-- name: DeleteTimingTap :exec
+DELETE FROM timing_taps WHERE id = ? AND session_id = ?
+`
+
+type DeleteTimingTapParams struct {
+	ID        int32
+	SessionID int32
+}
+
+func (q *Queries) DeleteTimingTap(ctx context.Context, arg DeleteTimingTapParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTimingTap,
+		arg.ID,
+		arg.SessionID,
+	)
+	return err
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users WHERE id = ?
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
+
+const deleteUserSessions = `-- name: DeleteUserSessions :exec
+DELETE FROM sessions WHERE user_id = ?
+`
+
+func (q *Queries) DeleteUserSessions(ctx context.Context, userID int32) error {
+	_, err := q.db.ExecContext(ctx, deleteUserSessions, userID)
+	return err
+}
+
+const deleteWorkout = `-- name: DeleteWorkout :exec
+DELETE FROM workouts WHERE id = ? AND athlete_id = ?
+`
+
+type DeleteWorkoutParams struct {
+	ID        int32
+	AthleteID int32
+}
+
+func (q *Queries) DeleteWorkout(ctx context.Context, arg DeleteWorkoutParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkout,
+		arg.ID,
+		arg.AthleteID,
+	)
+	return err
+}
+
+const finalizeTimingSession = `-- name: FinalizeTimingSession :execrows
+UPDATE timing_sessions SET status = 'finalized' WHERE id = ? AND status = 'running'
+`
+
+func (q *Queries) FinalizeTimingSession(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.ExecContext(ctx, finalizeTimingSession, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getActiveAthletes = `-- name: GetActiveAthletes :many
+SELECT id, name, grade, division, school_id, status, events, created_at
+FROM athletes
+WHERE status = 'active' AND school_id IN (SELECT id FROM schools WHERE is_home)
+ORDER BY name
+`
+
+func (q *Queries) GetActiveAthletes(ctx context.Context) ([]Athlete, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveAthletes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Athlete
+	for rows.Next() {
+		var i Athlete
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Grade,
+			&i.Division,
+			&i.SchoolID,
+			&i.Status,
+			&i.Events,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllAthletes = `-- name: GetAllAthletes :many
+SELECT id, name, grade, division, school_id, status, events, created_at
+FROM athletes
+ORDER BY name
+`
+
+func (q *Queries) GetAllAthletes(ctx context.Context) ([]Athlete, error) {
+	rows, err := q.db.QueryContext(ctx, getAllAthletes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Athlete
+	for rows.Next() {
+		var i Athlete
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Grade,
+			&i.Division,
+			&i.SchoolID,
+			&i.Status,
+			&i.Events,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllCourses = `-- name: GetAllCourses :many
+SELECT id, name, venue, distance_meters, surface, latitude, longitude, notes, created_at
+FROM courses
+ORDER BY name
+`
+
+func (q *Queries) GetAllCourses(ctx context.Context) ([]Course, error) {
+	rows, err := q.db.QueryContext(ctx, getAllCourses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Course
+	for rows.Next() {
+		var i Course
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Venue,
+			&i.DistanceMeters,
+			&i.Surface,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllMeets = `-- name: GetAllMeets :many
+SELECT id, name, date, location, course_id, season_id, description, created_at
+FROM meets
+ORDER BY date
+`
+
+func (q *Queries) GetAllMeets(ctx context.Context) ([]Meet, error) {
+	rows, err := q.db.QueryContext(ctx, getAllMeets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Meet
+	for rows.Next() {
+		var i Meet
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Date,
+			&i.Location,
+			&i.CourseID,
+			&i.SeasonID,
+			&i.Description,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllSeasons = `-- name: GetAllSeasons :many
+SELECT id, name, year, start_date, end_date, created_at
+FROM seasons
+ORDER BY year DESC
+`
+
+func (q *Queries) GetAllSeasons(ctx context.Context) ([]Season, error) {
+	rows, err := q.db.QueryContext(ctx, getAllSeasons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Season
+	for rows.Next() {
+		var i Season
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Year,
+			&i.StartDate,
+			&i.EndDate,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllUsers = `-- name: GetAllUsers :many
+SELECT id, username, password_hash, role, created_at FROM users ORDER BY username
+`
+
+func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getAllUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.PasswordHash,
+			&i.Role,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAthleteByID = `-- name: GetAthleteByID :one
+SELECT id, name, grade, division, school_id, status, events, created_at
+FROM athletes
+WHERE id = ?
+`
+
+func (q *Queries) GetAthleteByID(ctx context.Context, id int32) (Athlete, error) {
+	row := q.db.QueryRowContext(ctx, getAthleteByID, id)
+	var i Athlete
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Grade,
+		&i.Division,
+		&i.SchoolID,
+		&i.Status,
+		&i.Events,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAthleteHistory = `-- name: GetAthleteHistory :many
+SELECT r.id, r.status, r.time_ms, r.place, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date, m.season_id, s.name AS season_name, c.id AS course_id, c.name AS course_name, ra.id AS race_id, ra.name AS race_name, ra.distance_meters, sr.grade AS season_grade
+FROM results r
+JOIN meets m ON r.meet_id = m.id
+JOIN races ra ON r.race_id = ra.id
+LEFT JOIN seasons s ON m.season_id = s.id
+LEFT JOIN courses c ON m.course_id = c.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = r.athlete_id
+WHERE r.athlete_id = ?
+ORDER BY m.date, r.id
+`
+
+type GetAthleteHistoryRow struct {
+	ID             int32
+	Status         string
+	TimeMs         sql.NullInt32
+	Place          sql.NullInt32
+	MeetID         int32
+	MeetName       string
+	MeetDate       time.Time
+	SeasonID       sql.NullInt32
+	SeasonName     sql.NullString
+	CourseID       sql.NullInt32
+	CourseName     sql.NullString
+	RaceID         int32
+	RaceName       string
+	DistanceMeters int32
+	SeasonGrade    sql.NullInt32
+}
+
+func (q *Queries) GetAthleteHistory(ctx context.Context, athleteID int32) ([]GetAthleteHistoryRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAthleteHistory, athleteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAthleteHistoryRow
+	for rows.Next() {
+		var i GetAthleteHistoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+			&i.TimeMs,
+			&i.Place,
+			&i.MeetID,
+			&i.MeetName,
+			&i.MeetDate,
+			&i.SeasonID,
+			&i.SeasonName,
+			&i.CourseID,
+			&i.CourseName,
+			&i.RaceID,
+			&i.RaceName,
+			&i.DistanceMeters,
+			&i.SeasonGrade,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAthleteResultMarks = `-- name: GetAthleteResultMarks :many
+SELECT r.id, r.athlete_id, r.time_ms, ra.distance_meters, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date, m.season_id
+FROM results r
+JOIN races ra ON r.race_id = ra.id
+JOIN meets m ON r.meet_id = m.id
+WHERE r.athlete_id = ? AND r.status = 'finished'
+ORDER BY m.date, r.id
+`
+
+type GetAthleteResultMarksRow struct {
+	ID             int32
+	AthleteID      int32
+	TimeMs         sql.NullInt32
+	DistanceMeters int32
+	MeetID         int32
+	MeetName       string
+	MeetDate       time.Time
+	SeasonID       sql.NullInt32
+}
+
+func (q *Queries) GetAthleteResultMarks(ctx context.Context, athleteID int32) ([]GetAthleteResultMarksRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAthleteResultMarks, athleteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAthleteResultMarksRow
+	for rows.Next() {
+		var i GetAthleteResultMarksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AthleteID,
+			&i.TimeMs,
+			&i.DistanceMeters,
+			&i.MeetID,
+			&i.MeetName,
+			&i.MeetDate,
+			&i.SeasonID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAthleteWorkouts = `-- name: GetAthleteWorkouts :many
+SELECT id, athlete_id, date, type, distance_meters, duration_ms, effort, notes, created_at
+FROM workouts
+WHERE athlete_id = ? AND date >= ? AND date <= ?
+ORDER BY date DESC, id DESC
+`
+
+type GetAthleteWorkoutsParams struct {
+	AthleteID int32
+	FromDate  time.Time
+	ToDate    time.Time
+}
+
+func (q *Queries) GetAthleteWorkouts(ctx context.Context, arg GetAthleteWorkoutsParams) ([]Workout, error) {
+	rows, err := q.db.QueryContext(ctx, getAthleteWorkouts,
+		arg.AthleteID,
+		arg.FromDate,
+		arg.ToDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Workout
+	for rows.Next() {
+		var i Workout
+		if err := rows.Scan(
+			&i.ID,
+			&i.AthleteID,
+			&i.Date,
+			&i.Type,
+			&i.DistanceMeters,
+			&i.DurationMs,
+			&i.Effort,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCourseBestTimes = `-- name: GetCourseBestTimes :many
+SELECT r.id, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date, ra.id AS race_id, ra.name AS race_name
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN meets m ON r.meet_id = m.id
+JOIN races ra ON r.race_id = ra.id
+WHERE m.course_id = ? AND ra.distance_meters = ? AND a.division = ? AND a.school_id = ? AND r.status = 'finished'
+ORDER BY r.time_ms ASC
+LIMIT ?
+`
+
+type GetCourseBestTimesRow struct {
+	ID          int32
+	TimeMs      sql.NullInt32
+	Place       sql.NullInt32
+	AthleteID   int32
+	AthleteName string
+	MeetID      int32
+	MeetName    string
+	MeetDate    time.Time
+	RaceID      int32
+	RaceName    string
+}
+
+type GetCourseBestTimesParams struct {
+	CourseID       sql.NullInt32
+	DistanceMeters int32
+	Division       string
+	SchoolID       int32
+	Limit          int32
+}
+
+func (q *Queries) GetCourseBestTimes(ctx context.Context, arg GetCourseBestTimesParams) ([]GetCourseBestTimesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCourseBestTimes,
+		arg.CourseID,
+		arg.DistanceMeters,
+		arg.Division,
+		arg.SchoolID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCourseBestTimesRow
+	for rows.Next() {
+		var i GetCourseBestTimesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TimeMs,
+			&i.Place,
+			&i.AthleteID,
+			&i.AthleteName,
+			&i.MeetID,
+			&i.MeetName,
+			&i.MeetDate,
+			&i.RaceID,
+			&i.RaceName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCourseByID = `-- name: GetCourseByID :one
+SELECT id, name, venue, distance_meters, surface, latitude, longitude, notes, created_at
+FROM courses
+WHERE id = ?
+`
+
+func (q *Queries) GetCourseByID(ctx context.Context, id int32) (Course, error) {
+	row := q.db.QueryRowContext(ctx, getCourseByID, id)
+	var i Course
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Venue,
+		&i.DistanceMeters,
+		&i.Surface,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getCourseResults = `-- name: GetCourseResults :many
+SELECT r.id, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, a.division AS athlete_division, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date, ra.id AS race_id, ra.name AS race_name, ra.distance_meters
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN meets m ON r.meet_id = m.id
+JOIN races ra ON r.race_id = ra.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+WHERE m.course_id = ? AND r.status = 'finished'
+  AND (? IS NULL OR a.school_id = ?)
+ORDER BY m.date, ra.id, r.place
+`
+
+type GetCourseResultsRow struct {
+	ID              int32
+	TimeMs          sql.NullInt32
+	Place           sql.NullInt32
+	AthleteID       int32
+	AthleteName     string
+	AthleteGrade    int32
+	SeasonGrade     sql.NullInt32
+	AthleteDivision string
+	MeetID          int32
+	MeetName        string
+	MeetDate        time.Time
+	RaceID          int32
+	RaceName        string
+	DistanceMeters  int32
+}
+
+type GetCourseResultsParams struct {
+	CourseID sql.NullInt32
+	SchoolID sql.NullInt32
+}
+
+func (q *Queries) GetCourseResults(ctx context.Context, arg GetCourseResultsParams) ([]GetCourseResultsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCourseResults,
+		arg.CourseID,
+		arg.SchoolID,
+		arg.SchoolID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCourseResultsRow
+	for rows.Next() {
+		var i GetCourseResultsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TimeMs,
+			&i.Place,
+			&i.AthleteID,
+			&i.AthleteName,
+			&i.AthleteGrade,
+			&i.SeasonGrade,
+			&i.AthleteDivision,
+			&i.MeetID,
+			&i.MeetName,
+			&i.MeetDate,
+			&i.RaceID,
+			&i.RaceName,
+			&i.DistanceMeters,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEntryByBib = `-- name: GetEntryByBib :one
+SELECT e.race_id, a.id AS athlete_id, a.name AS athlete_name
+FROM entries e
+JOIN athletes a ON e.athlete_id = a.id
+WHERE e.meet_id = ? AND e.bib = ?
+`
+
+type GetEntryByBibRow struct {
+	RaceID      int32
+	AthleteID   int32
+	AthleteName string
+}
+
+type GetEntryByBibParams struct {
+	MeetID int32
+	Bib    sql.NullInt32
+}
+
+func (q *Queries) GetEntryByBib(ctx context.Context, arg GetEntryByBibParams) (GetEntryByBibRow, error) {
+	row := q.db.QueryRowContext(ctx, getEntryByBib,
+		arg.MeetID,
+		arg.Bib,
+	)
+	var i GetEntryByBibRow
+	err := row.Scan(
+		&i.RaceID,
+		&i.AthleteID,
+		&i.AthleteName,
+	)
+	return i, err
+}
+
+const getHomeSchool = `-- name: GetHomeSchool :one
+SELECT id, name, is_home, created_at
+FROM schools
+WHERE is_home
+LIMIT 1
+`
+
+func (q *Queries) GetHomeSchool(ctx context.Context) (School, error) {
+	row := q.db.QueryRowContext(ctx, getHomeSchool)
+	var i School
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.IsHome,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLatestSeason = `-- name: GetLatestSeason :one
+SELECT id, name, year, start_date, end_date, created_at
+FROM seasons
+ORDER BY year DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestSeason(ctx context.Context) (Season, error) {
+	row := q.db.QueryRowContext(ctx, getLatestSeason)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Year,
+		&i.StartDate,
+		&i.EndDate,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getMeetByID = `-- name: GetMeetByID :one
+SELECT id, name, date, location, course_id, season_id, description, created_at
+FROM meets
+WHERE id = ?
+`
+
+func (q *Queries) GetMeetByID(ctx context.Context, id int32) (Meet, error) {
+	row := q.db.QueryRowContext(ctx, getMeetByID, id)
+	var i Meet
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Date,
+		&i.Location,
+		&i.CourseID,
+		&i.SeasonID,
+		&i.Description,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getMeetEntries = `-- name: GetMeetEntries :many
+SELECT e.id, e.race_id, e.bib, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, a.division AS athlete_division
+FROM entries e
+JOIN athletes a ON e.athlete_id = a.id
+JOIN meets m ON e.meet_id = m.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+WHERE e.meet_id = ?
+ORDER BY e.race_id, a.name
+`
+
+type GetMeetEntriesRow struct {
+	ID              int32
+	RaceID          int32
+	Bib             sql.NullInt32
+	AthleteID       int32
+	AthleteName     string
+	AthleteGrade    int32
+	SeasonGrade     sql.NullInt32
+	AthleteDivision string
+}
+
+func (q *Queries) GetMeetEntries(ctx context.Context, meetID int32) ([]GetMeetEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getMeetEntries, meetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMeetEntriesRow
+	for rows.Next() {
+		var i GetMeetEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RaceID,
+			&i.Bib,
+			&i.AthleteID,
+			&i.AthleteName,
+			&i.AthleteGrade,
+			&i.SeasonGrade,
+			&i.AthleteDivision,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMeetResult = `-- name: GetMeetResult :one
+SELECT r.id, r.meet_id, r.status, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, sc.id AS school_id, sc.name AS school_name, ra.id AS race_id, ra.name AS race_name, ra.distance_meters
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN schools sc ON a.school_id = sc.id
+JOIN races ra ON r.race_id = ra.id
+JOIN meets m ON r.meet_id = m.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+WHERE r.id = ?
+`
+
+type GetMeetResultRow struct {
+	ID             int32
+	MeetID         int32
+	Status         string
+	TimeMs         sql.NullInt32
+	Place          sql.NullInt32
+	AthleteID      int32
+	AthleteName    string
+	AthleteGrade   int32
+	SeasonGrade    sql.NullInt32
+	SchoolID       int32
+	SchoolName     string
+	RaceID         int32
+	RaceName       string
+	DistanceMeters int32
+}
+
+func (q *Queries) GetMeetResult(ctx context.Context, id int32) (GetMeetResultRow, error) {
+	row := q.db.QueryRowContext(ctx, getMeetResult, id)
+	var i GetMeetResultRow
+	err := row.Scan(
+		&i.ID,
+		&i.MeetID,
+		&i.Status,
+		&i.TimeMs,
+		&i.Place,
+		&i.AthleteID,
+		&i.AthleteName,
+		&i.AthleteGrade,
+		&i.SeasonGrade,
+		&i.SchoolID,
+		&i.SchoolName,
+		&i.RaceID,
+		&i.RaceName,
+		&i.DistanceMeters,
+	)
+	return i, err
+}
+
+const getMeetResults = `-- name: GetMeetResults :many
+SELECT r.id, r.status, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, sc.id AS school_id, sc.name AS school_name, ra.id AS race_id, ra.name AS race_name
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN schools sc ON a.school_id = sc.id
+JOIN races ra ON r.race_id = ra.id
+JOIN meets m ON r.meet_id = m.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+WHERE r.meet_id = ?
+ORDER BY ra.start_time, ra.id, r.place IS NULL, r.place
+`
+
+type GetMeetResultsRow struct {
+	ID           int32
+	Status       string
+	TimeMs       sql.NullInt32
+	Place        sql.NullInt32
+	AthleteID    int32
+	AthleteName  string
+	AthleteGrade int32
+	SeasonGrade  sql.NullInt32
+	SchoolID     int32
+	SchoolName   string
+	RaceID       int32
+	RaceName     string
+}
+
+func (q *Queries) GetMeetResults(ctx context.Context, meetID int32) ([]GetMeetResultsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getMeetResults, meetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMeetResultsRow
+	for rows.Next() {
+		var i GetMeetResultsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+			&i.TimeMs,
+			&i.Place,
+			&i.AthleteID,
+			&i.AthleteName,
+			&i.AthleteGrade,
+			&i.SeasonGrade,
+			&i.SchoolID,
+			&i.SchoolName,
+			&i.RaceID,
+			&i.RaceName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMeetTimingSessions = `-- name: GetMeetTimingSessions :many
+SELECT ts.id, ts.meet_id, ts.race_id, ra.name AS race_name, ts.status, ts.started_at
+FROM timing_sessions ts
+JOIN races ra ON ts.race_id = ra.id
+WHERE ts.meet_id = ?
+ORDER BY ts.started_at, ts.id
+`
+
+type GetMeetTimingSessionsRow struct {
+	ID        int32
+	MeetID    int32
+	RaceID    int32
+	RaceName  string
+	Status    string
+	StartedAt time.Time
+}
+
+func (q *Queries) GetMeetTimingSessions(ctx context.Context, meetID int32) ([]GetMeetTimingSessionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getMeetTimingSessions, meetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMeetTimingSessionsRow
+	for rows.Next() {
+		var i GetMeetTimingSessionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MeetID,
+			&i.RaceID,
+			&i.RaceName,
+			&i.Status,
+			&i.StartedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPreviousSeason = `-- name: GetPreviousSeason :one
+SELECT id, name, year, start_date, end_date, created_at
+FROM seasons
+WHERE year < ?
+ORDER BY year DESC
+LIMIT 1
+`
+
+func (q *Queries) GetPreviousSeason(ctx context.Context, year int32) (Season, error) {
+	row := q.db.QueryRowContext(ctx, getPreviousSeason, year)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Year,
+		&i.StartDate,
+		&i.EndDate,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRaceByID = `-- name: GetRaceByID :one
+SELECT id, meet_id, name, distance_meters, entry_limit, start_time, created_at
+FROM races
+WHERE id = ?
+`
+
+func (q *Queries) GetRaceByID(ctx context.Context, id int32) (Race, error) {
+	row := q.db.QueryRowContext(ctx, getRaceByID, id)
+	var i Race
+	err := row.Scan(
+		&i.ID,
+		&i.MeetID,
+		&i.Name,
+		&i.DistanceMeters,
+		&i.EntryLimit,
+		&i.StartTime,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRaceForUpdate = `-- name: GetRaceForUpdate :one
+SELECT id, meet_id, name, distance_meters, entry_limit, start_time, created_at
+FROM races
+WHERE id = ?
+FOR UPDATE
+`
+
+func (q *Queries) GetRaceForUpdate(ctx context.Context, id int32) (Race, error) {
+	row := q.db.QueryRowContext(ctx, getRaceForUpdate, id)
+	var i Race
+	err := row.Scan(
+		&i.ID,
+		&i.MeetID,
+		&i.Name,
+		&i.DistanceMeters,
+		&i.EntryLimit,
+		&i.StartTime,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRaceResults = `-- name: GetRaceResults :many
+SELECT r.id, r.status, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, sc.id AS school_id, sc.name AS school_name
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN schools sc ON a.school_id = sc.id
+JOIN meets m ON r.meet_id = m.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+WHERE r.race_id = ?
+ORDER BY r.place IS NULL, r.place
+`
+
+type GetRaceResultsRow struct {
+	ID           int32
+	Status       string
+	TimeMs       sql.NullInt32
+	Place        sql.NullInt32
+	AthleteID    int32
+	AthleteName  string
+	AthleteGrade int32
+	SeasonGrade  sql.NullInt32
+	SchoolID     int32
+	SchoolName   string
+}
+
+func (q *Queries) GetRaceResults(ctx context.Context, raceID int32) ([]GetRaceResultsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRaceResults, raceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRaceResultsRow
+	for rows.Next() {
+		var i GetRaceResultsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+			&i.TimeMs,
+			&i.Place,
+			&i.AthleteID,
+			&i.AthleteName,
+			&i.AthleteGrade,
+			&i.SeasonGrade,
+			&i.SchoolID,
+			&i.SchoolName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRacesByMeetID = `-- name: GetRacesByMeetID :many
+SELECT id, meet_id, name, distance_meters, entry_limit, start_time, created_at
+FROM races
+WHERE meet_id = ?
+ORDER BY start_time, id
+`
+
+func (q *Queries) GetRacesByMeetID(ctx context.Context, meetID int32) ([]Race, error) {
+	rows, err := q.db.QueryContext(ctx, getRacesByMeetID, meetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Race
+	for rows.Next() {
+		var i Race
+		if err := rows.Scan(
+			&i.ID,
+			&i.MeetID,
+			&i.Name,
+			&i.DistanceMeters,
+			&i.EntryLimit,
+			&i.StartTime,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getResultMarksForAthletes = `-- name: GetResultMarksForAthletes :many
+SELECT r.id, r.athlete_id, r.time_ms, ra.distance_meters, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date, m.season_id
+FROM results r
+JOIN races ra ON r.race_id = ra.id
+JOIN meets m ON r.meet_id = m.id
+WHERE r.status = 'finished' AND r.athlete_id IN (/*SLICE:athlete_ids*/?)
+ORDER BY m.date, r.id
+`
+
+type GetResultMarksForAthletesRow struct {
+	ID             int32
+	AthleteID      int32
+	TimeMs         sql.NullInt32
+	DistanceMeters int32
+	MeetID         int32
+	MeetName       string
+	MeetDate       time.Time
+	SeasonID       sql.NullInt32
+}
+
+func (q *Queries) GetResultMarksForAthletes(ctx context.Context, athleteIds []int32) ([]GetResultMarksForAthletesRow, error) {
+	query := getResultMarksForAthletes
+	var queryParams []interface{}
+	if len(athleteIds) > 0 {
+		for _, v := range athleteIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:athlete_ids*/?", strings.Repeat(",?", len(athleteIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:athlete_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetResultMarksForAthletesRow
+	for rows.Next() {
+		var i GetResultMarksForAthletesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AthleteID,
+			&i.TimeMs,
+			&i.DistanceMeters,
+			&i.MeetID,
+			&i.MeetName,
+			&i.MeetDate,
+			&i.SeasonID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getResultRace = `-- name: GetResultRace :one
+SELECT r.id, r.status, r.time_ms, ra.id AS race_id, ra.distance_meters
+FROM results r
+JOIN races ra ON r.race_id = ra.id
+WHERE r.id = ?
+`
+
+type GetResultRaceRow struct {
+	ID             int32
+	Status         string
+	TimeMs         sql.NullInt32
+	RaceID         int32
+	DistanceMeters int32
+}
+
+func (q *Queries) GetResultRace(ctx context.Context, id int32) (GetResultRaceRow, error) {
+	row := q.db.QueryRowContext(ctx, getResultRace, id)
+	var i GetResultRaceRow
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.TimeMs,
+		&i.RaceID,
+		&i.DistanceMeters,
+	)
+	return i, err
+}
+
+const getResultSplits = `-- name: GetResultSplits :many
+SELECT id, result_id, distance_meters, elapsed_ms, created_at
+FROM splits
+WHERE result_id = ?
+ORDER BY distance_meters
+`
+
+func (q *Queries) GetResultSplits(ctx context.Context, resultID int32) ([]Split, error) {
+	rows, err := q.db.QueryContext(ctx, getResultSplits, resultID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Split
+	for rows.Next() {
+		var i Split
+		if err := rows.Scan(
+			&i.ID,
+			&i.ResultID,
+			&i.DistanceMeters,
+			&i.ElapsedMs,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getResultsByMeetID = `-- name: GetResultsByMeetID :many
+SELECT r.id, r.athlete_id, r.meet_id, r.race_id, r.status, r.time_ms, r.place, r.created_at
+FROM results r
+WHERE r.meet_id = ?
+ORDER BY r.race_id, r.place IS NULL, r.place
+`
+
+func (q *Queries) GetResultsByMeetID(ctx context.Context, meetID int32) ([]Result, error) {
+	rows, err := q.db.QueryContext(ctx, getResultsByMeetID, meetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Result
+	for rows.Next() {
+		var i Result
+		if err := rows.Scan(
+			&i.ID,
+			&i.AthleteID,
+			&i.MeetID,
+			&i.RaceID,
+			&i.Status,
+			&i.TimeMs,
+			&i.Place,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSchoolAthletes = `-- name: GetSchoolAthletes :many
+SELECT id, name, grade, division, school_id, status, events, created_at
+FROM athletes
+WHERE school_id = ?
+ORDER BY division, name
+`
+
+func (q *Queries) GetSchoolAthletes(ctx context.Context, schoolID int32) ([]Athlete, error) {
+	rows, err := q.db.QueryContext(ctx, getSchoolAthletes, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Athlete
+	for rows.Next() {
+		var i Athlete
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Grade,
+			&i.Division,
+			&i.SchoolID,
+			&i.Status,
+			&i.Events,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSchoolByID = `-- name: GetSchoolByID :one
+SELECT id, name, is_home, created_at
+FROM schools
+WHERE id = ?
+`
+
+func (q *Queries) GetSchoolByID(ctx context.Context, id int32) (School, error) {
+	row := q.db.QueryRowContext(ctx, getSchoolByID, id)
+	var i School
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.IsHome,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSchoolByName = `-- name: GetSchoolByName :one
+SELECT id, name, is_home, created_at
+FROM schools
+WHERE name = ?
+`
+
+func (q *Queries) GetSchoolByName(ctx context.Context, name string) (School, error) {
+	row := q.db.QueryRowContext(ctx, getSchoolByName, name)
+	var i School
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.IsHome,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSchools = `-- name: GetSchools :many
+SELECT id, name, is_home, created_at
+FROM schools
+ORDER BY is_home DESC, name
+`
+
+func (q *Queries) GetSchools(ctx context.Context) ([]School, error) {
+	rows, err := q.db.QueryContext(ctx, getSchools)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []School
+	for rows.Next() {
+		var i School
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.IsHome,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSeasonByID = `-- name: GetSeasonByID :one
+SELECT id, name, year, start_date, end_date, created_at
+FROM seasons
+WHERE id = ?
+`
+
+func (q *Queries) GetSeasonByID(ctx context.Context, id int32) (Season, error) {
+	row := q.db.QueryRowContext(ctx, getSeasonByID, id)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Year,
+		&i.StartDate,
+		&i.EndDate,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSeasonForDate = `-- name: GetSeasonForDate :one
+SELECT id, name, year, start_date, end_date, created_at
+FROM seasons
+WHERE start_date <= ? AND end_date >= ?
+LIMIT 1
+`
+
+type GetSeasonForDateParams struct {
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+func (q *Queries) GetSeasonForDate(ctx context.Context, arg GetSeasonForDateParams) (Season, error) {
+	row := q.db.QueryRowContext(ctx, getSeasonForDate,
+		arg.StartDate,
+		arg.EndDate,
+	)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Year,
+		&i.StartDate,
+		&i.EndDate,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSeasonMeets = `-- name: GetSeasonMeets :many
+SELECT id, name, date, location, course_id, season_id, description, created_at
+FROM meets
+WHERE season_id = ?
+ORDER BY date
+`
+
+func (q *Queries) GetSeasonMeets(ctx context.Context, seasonID sql.NullInt32) ([]Meet, error) {
+	rows, err := q.db.QueryContext(ctx, getSeasonMeets, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Meet
+	for rows.Next() {
+		var i Meet
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Date,
+			&i.Location,
+			&i.CourseID,
+			&i.SeasonID,
+			&i.Description,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSeasonRoster = `-- name: GetSeasonRoster :many
+SELECT a.id, a.name, sr.grade, a.division, a.status
+FROM season_rosters sr
+JOIN athletes a ON sr.athlete_id = a.id
+WHERE sr.season_id = ?
+ORDER BY a.division, a.name
+`
+
+type GetSeasonRosterRow struct {
+	ID       int32
+	Name     string
+	Grade    int32
+	Division string
+	Status   string
+}
+
+func (q *Queries) GetSeasonRoster(ctx context.Context, seasonID int32) ([]GetSeasonRosterRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSeasonRoster, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSeasonRosterRow
+	for rows.Next() {
+		var i GetSeasonRosterRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Grade,
+			&i.Division,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSessionUser = `-- name: GetSessionUser :one
+SELECT u.id, u.username, u.role, s.expires_at
+FROM sessions s
+JOIN users u ON s.user_id = u.id
+WHERE s.token_hash = ?
+`
+
+type GetSessionUserRow struct {
+	ID        int32
+	Username  string
+	Role      string
+	ExpiresAt time.Time
+}
+
+func (q *Queries) GetSessionUser(ctx context.Context, tokenHash string) (GetSessionUserRow, error) {
+	row := q.db.QueryRowContext(ctx, getSessionUser, tokenHash)
+	var i GetSessionUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Role,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getTimingSession = `-- name: GetTimingSession :one
+SELECT ts.id, ts.meet_id, ts.race_id, ra.name AS race_name, ts.status, ts.started_at
+FROM timing_sessions ts
+JOIN races ra ON ts.race_id = ra.id
+WHERE ts.id = ?
+`
+
+type GetTimingSessionRow struct {
+	ID        int32
+	MeetID    int32
+	RaceID    int32
+	RaceName  string
+	Status    string
+	StartedAt time.Time
+}
+
+func (q *Queries) GetTimingSession(ctx context.Context, id int32) (GetTimingSessionRow, error) {
+	row := q.db.QueryRowContext(ctx, getTimingSession, id)
+	var i GetTimingSessionRow
+	err := row.Scan(
+		&i.ID,
+		&i.MeetID,
+		&i.RaceID,
+		&i.RaceName,
+		&i.Status,
+		&i.StartedAt,
+	)
+	return i, err
+}
+
+const getTimingTaps = `-- name: GetTimingTaps :many
+SELECT t.id, t.elapsed_ms, t.athlete_id, a.name AS athlete_name, t.recorded_at
+FROM timing_taps t
+LEFT JOIN athletes a ON t.athlete_id = a.id
+WHERE t.session_id = ?
+ORDER BY t.elapsed_ms, t.id
+`
+
+type GetTimingTapsRow struct {
+	ID          int32
+	ElapsedMs   int32
+	AthleteID   sql.NullInt32
+	AthleteName sql.NullString
+	RecordedAt  time.Time
+}
+
+func (q *Queries) GetTimingTaps(ctx context.Context, sessionID int32) ([]GetTimingTapsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTimingTaps, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTimingTapsRow
+	for rows.Next() {
+		var i GetTimingTapsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ElapsedMs,
+			&i.AthleteID,
+			&i.AthleteName,
+			&i.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTopTimes = `-- name: GetTopTimes :many
+SELECT r.id, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date, ra.id AS race_id, ra.name AS race_name
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN meets m ON r.meet_id = m.id
+JOIN races ra ON r.race_id = ra.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+WHERE r.status = 'finished'
+  AND ra.distance_meters = ? AND a.division = ?
+  AND a.school_id = ?
+  AND m.date >= ? AND m.date <= ?
+  AND (? IS NULL OR m.season_id = ?)
+  AND (? IS NULL OR m.course_id = ?)
+  AND (? IS NULL OR sr.grade = ? OR (sr.grade IS NULL AND a.grade = ?))
+ORDER BY r.time_ms ASC, m.date ASC, r.id
+LIMIT ?
+`
+
+type GetTopTimesRow struct {
+	ID          int32
+	TimeMs      sql.NullInt32
+	Place       sql.NullInt32
+	AthleteID   int32
+	AthleteName string
+	MeetID      int32
+	MeetName    string
+	MeetDate    time.Time
+	RaceID      int32
+	RaceName    string
+}
+
+type GetTopTimesParams struct {
+	DistanceMeters int32
+	Division       string
+	SchoolID       int32
+	FromDate       time.Time
+	ToDate         time.Time
+	SeasonID       sql.NullInt32
+	CourseID       sql.NullInt32
+	Grade          sql.NullInt32
+	Limit          int32
+}
+
+func (q *Queries) GetTopTimes(ctx context.Context, arg GetTopTimesParams) ([]GetTopTimesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTopTimes,
+		arg.DistanceMeters,
+		arg.Division,
+		arg.SchoolID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.SeasonID,
+		arg.SeasonID,
+		arg.CourseID,
+		arg.CourseID,
+		arg.Grade,
+		arg.Grade,
+		arg.Grade,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTopTimesRow
+	for rows.Next() {
+		var i GetTopTimesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TimeMs,
+			&i.Place,
+			&i.AthleteID,
+			&i.AthleteName,
+			&i.MeetID,
+			&i.MeetName,
+			&i.MeetDate,
+			&i.RaceID,
+			&i.RaceName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnenteredResults = `-- name: GetUnenteredResults :many
+SELECT r.id, r.status, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, sc.id AS school_id, sc.name AS school_name, ra.id AS race_id, ra.name AS race_name, ra.distance_meters, e.race_id AS entered_race_id
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN schools sc ON a.school_id = sc.id
+JOIN races ra ON r.race_id = ra.id
+JOIN meets m ON r.meet_id = m.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+LEFT JOIN entries e ON e.meet_id = r.meet_id AND e.athlete_id = r.athlete_id
+WHERE r.meet_id = ? AND sc.is_home AND (e.id IS NULL OR e.race_id <> r.race_id)
+ORDER BY ra.start_time, ra.id, r.place IS NULL, r.place
+`
+
+type GetUnenteredResultsRow struct {
+	ID             int32
+	Status         string
+	TimeMs         sql.NullInt32
+	Place          sql.NullInt32
+	AthleteID      int32
+	AthleteName    string
+	AthleteGrade   int32
+	SeasonGrade    sql.NullInt32
+	SchoolID       int32
+	SchoolName     string
+	RaceID         int32
+	RaceName       string
+	DistanceMeters int32
+	EnteredRaceID  sql.NullInt32
+}
+
+func (q *Queries) GetUnenteredResults(ctx context.Context, meetID int32) ([]GetUnenteredResultsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUnenteredResults, meetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUnenteredResultsRow
+	for rows.Next() {
+		var i GetUnenteredResultsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+			&i.TimeMs,
+			&i.Place,
+			&i.AthleteID,
+			&i.AthleteName,
+			&i.AthleteGrade,
+			&i.SeasonGrade,
+			&i.SchoolID,
+			&i.SchoolName,
+			&i.RaceID,
+			&i.RaceName,
+			&i.DistanceMeters,
+			&i.EnteredRaceID,
 		); err != nil {
 			return nil, err
 		}
@@ -137,28 +2422,114 @@ func (q *Queries) GetAllAthletes(ctx context.Context) ([]Athlete, error) {
 	return items, nil
 }
 
-const getAllMeets = `-- name: GetAllMeets :many
-SELECT id, name, date, location, description, created_at
-FROM meets
-ORDER BY date
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, username, password_hash, role, created_at FROM users WHERE id = ?
 `
 
-func (q *Queries) GetAllMeets(ctx context.Context) ([]Meet, error) {
-	rows, err := q.db.QueryContext(ctx, getAllMeets)
+func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserByUsername = `-- name: GetUserByUsername :one
+SELECT id, username, password_hash, role, created_at FROM users WHERE username = ?
+`
+
+func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByUsername, username)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWorkout = `-- name: GetWorkout :one
+SELECT id, athlete_id, date, type, distance_meters, duration_ms, effort, notes, created_at
+FROM workouts
+WHERE id = ? AND athlete_id = ?
+`
+
+type GetWorkoutParams struct {
+	ID        int32
+	AthleteID int32
+}
+
+func (q *Queries) GetWorkout(ctx context.Context, arg GetWorkoutParams) (Workout, error) {
+	row := q.db.QueryRowContext(ctx, getWorkout,
+		arg.ID,
+		arg.AthleteID,
+	)
+	var i Workout
+	err := row.Scan(
+		&i.ID,
+		&i.AthleteID,
+		&i.Date,
+		&i.Type,
+		&i.DistanceMeters,
+		&i.DurationMs,
+		&i.Effort,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWorkoutsBetween = `-- name: GetWorkoutsBetween :many
+SELECT w.id, w.date, w.distance_meters, w.duration_ms, a.id AS athlete_id, a.name AS athlete_name, a.division AS athlete_division
+FROM workouts w
+JOIN athletes a ON w.athlete_id = a.id
+WHERE w.date >= ? AND w.date <= ?
+ORDER BY w.date, w.id
+`
+
+type GetWorkoutsBetweenRow struct {
+	ID              int32
+	Date            time.Time
+	DistanceMeters  int32
+	DurationMs      sql.NullInt32
+	AthleteID       int32
+	AthleteName     string
+	AthleteDivision string
+}
+
+type GetWorkoutsBetweenParams struct {
+	FromDate time.Time
+	ToDate   time.Time
+}
+
+func (q *Queries) GetWorkoutsBetween(ctx context.Context, arg GetWorkoutsBetweenParams) ([]GetWorkoutsBetweenRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkoutsBetween,
+		arg.FromDate,
+		arg.ToDate,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Meet
+	var items []GetWorkoutsBetweenRow
 	for rows.Next() {
-		var i Meet
+		var i GetWorkoutsBetweenRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Name,
 			&i.Date,
-			&i.Location,
-			&i.Description,
-			&i.CreatedAt,
+			&i.DistanceMeters,
+			&i.DurationMs,
+			&i.AthleteID,
+			&i.AthleteName,
+			&i.AthleteDivision,
 		); err != nil {
 			return nil, err
 		}
@@ -173,59 +2544,80 @@ func (q *Queries) GetAllMeets(ctx context.Context) ([]Meet, error) {
 	return items, nil
 }
 
-const getAthleteByID = `-- name: GetAthleteByID :one
-SELECT id, name, grade, personal_record, events, created_at
-FROM athletes
-WHERE id = ?
+const graduateSeniors = `-- name: GraduateSeniors :execrows
+UPDATE athletes
+SET status = 'alumni'
+WHERE status = 'active' AND grade >= 12
+  AND school_id IN (SELECT id FROM schools WHERE is_home)
 `
 
-func (q *Queries) GetAthleteByID(ctx context.Context, id int32) (Athlete, error) {
-	row := q.db.QueryRowContext(ctx, getAthleteByID, id)
-	var i Athlete
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Grade,
-		&i.PersonalRecord,
-		&i.Events,
-		&i.CreatedAt,
-	)
-	return i, err
+func (q *Queries) GraduateSeniors(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, graduateSeniors)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const getMeetResults = `-- name: GetMeetResults :many
-SELECT r.id, r.time, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade
-FROM results r
-JOIN athletes a ON r.athlete_id = a.id
-WHERE r.meet_id = ?
-ORDER BY r.place
+const listAthletes = `-- name: ListAthletes :many
+SELECT id, name, grade, division, school_id, status, events, created_at
+FROM athletes
+WHERE school_id = ?
+  AND grade >= ? AND grade <= ?
+  AND division LIKE ? AND status LIKE ?
+  AND name LIKE ?
+ORDER BY
+  CASE WHEN ? = 'name' THEN name END ASC,
+  CASE WHEN ? = '-name' THEN name END DESC,
+  CASE WHEN ? = 'grade' THEN grade END ASC,
+  CASE WHEN ? = '-grade' THEN grade END DESC,
+  name, id
+LIMIT ? OFFSET ?
 `
 
-type GetMeetResultsRow struct {
-	ID           int32
-	Time         string
-	Place        int32
-	AthleteID    int32
-	AthleteName  string
-	AthleteGrade int32
+type ListAthletesParams struct {
+	SchoolID int32
+	MinGrade int32
+	MaxGrade int32
+	Division string
+	Status   string
+	Name     string
+	Sort     interface{}
+	Limit    int32
+	Offset   int32
 }
 
-func (q *Queries) GetMeetResults(ctx context.Context, meetID int32) ([]GetMeetResultsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getMeetResults, meetID)
+func (q *Queries) ListAthletes(ctx context.Context, arg ListAthletesParams) ([]Athlete, error) {
+	rows, err := q.db.QueryContext(ctx, listAthletes,
+		arg.SchoolID,
+		arg.MinGrade,
+		arg.MaxGrade,
+		arg.Division,
+		arg.Status,
+		arg.Name,
+		arg.Sort,
+		arg.Sort,
+		arg.Sort,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetMeetResultsRow
+	var items []Athlete
 	for rows.Next() {
-		var i GetMeetResultsRow
+		var i Athlete
 		if err := rows.Scan(
 			&i.ID,
-			&i.Time,
-			&i.Place,
-			&i.AthleteID,
-			&i.AthleteName,
-			&i.AthleteGrade,
+			&i.Name,
+			&i.Grade,
+			&i.Division,
+			&i.SchoolID,
+			&i.Status,
+			&i.Events,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -240,29 +2632,92 @@ func (q *Queries) GetMeetResults(ctx context.Context, meetID int32) ([]GetMeetRe
 	return items, nil
 }
 
-const getResultsByMeetID = `-- name: GetResultsByMeetID :many
-SELECT r.id, r.athlete_id, r.meet_id, r.time, r.place, r.created_at
+const listMeetResults = `-- name: ListMeetResults :many
+SELECT r.id, r.status, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, sc.id AS school_id, sc.name AS school_name, ra.id AS race_id, ra.name AS race_name, ra.distance_meters
 FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN schools sc ON a.school_id = sc.id
+JOIN races ra ON r.race_id = ra.id
+JOIN meets m ON r.meet_id = m.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
 WHERE r.meet_id = ?
-ORDER BY r.place
+  AND r.race_id >= ? AND r.race_id <= ?
+  AND a.division LIKE ? AND a.name LIKE ?
+ORDER BY ra.start_time, ra.id, r.place IS NULL,
+  CASE WHEN ? = 'place' THEN r.place END ASC,
+  CASE WHEN ? = '-place' THEN r.place END DESC,
+  CASE WHEN ? = 'time' THEN r.time_ms END ASC,
+  CASE WHEN ? = '-time' THEN r.time_ms END DESC,
+  CASE WHEN ? = 'name' THEN a.name END ASC,
+  CASE WHEN ? = '-name' THEN a.name END DESC,
+  r.id
+LIMIT ? OFFSET ?
 `
 
-func (q *Queries) GetResultsByMeetID(ctx context.Context, meetID int32) ([]Result, error) {
-	rows, err := q.db.QueryContext(ctx, getResultsByMeetID, meetID)
+type ListMeetResultsRow struct {
+	ID             int32
+	Status         string
+	TimeMs         sql.NullInt32
+	Place          sql.NullInt32
+	AthleteID      int32
+	AthleteName    string
+	AthleteGrade   int32
+	SeasonGrade    sql.NullInt32
+	SchoolID       int32
+	SchoolName     string
+	RaceID         int32
+	RaceName       string
+	DistanceMeters int32
+}
+
+type ListMeetResultsParams struct {
+	MeetID    int32
+	MinRaceID int32
+	MaxRaceID int32
+	Division  string
+	Name      string
+	Sort      interface{}
+	Limit     int32
+	Offset    int32
+}
+
+func (q *Queries) ListMeetResults(ctx context.Context, arg ListMeetResultsParams) ([]ListMeetResultsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMeetResults,
+		arg.MeetID,
+		arg.MinRaceID,
+		arg.MaxRaceID,
+		arg.Division,
+		arg.Name,
+		arg.Sort,
+		arg.Sort,
+		arg.Sort,
+		arg.Sort,
+		arg.Sort,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Result
+	var items []ListMeetResultsRow
 	for rows.Next() {
-		var i Result
+		var i ListMeetResultsRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.AthleteID,
-			&i.MeetID,
-			&i.Time,
+			&i.Status,
+			&i.TimeMs,
 			&i.Place,
-			&i.CreatedAt,
+			&i.AthleteID,
+			&i.AthleteName,
+			&i.AthleteGrade,
+			&i.SeasonGrade,
+			&i.SchoolID,
+			&i.SchoolName,
+			&i.RaceID,
+			&i.RaceName,
+			&i.DistanceMeters,
 		); err != nil {
 			return nil, err
 		}
@@ -277,44 +2732,59 @@ func (q *Queries) GetResultsByMeetID(ctx context.Context, meetID int32) ([]Resul
 	return items, nil
 }
 
-const getTopTimes = `-- name: GetTopTimes :many
-SELECT r.id, r.time, r.place, a.id AS athlete_id, a.name AS athlete_name, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date
-FROM results r
-JOIN athletes a ON r.athlete_id = a.id
-JOIN meets m ON r.meet_id = m.id
-ORDER BY r.time ASC
-LIMIT 10
+const listMeets = `-- name: ListMeets :many
+SELECT id, name, date, location, course_id, season_id, description, created_at
+FROM meets
+WHERE date >= ? AND date <= ?
+  AND location LIKE ? AND name LIKE ?
+ORDER BY
+  CASE WHEN ? = 'date' THEN date END ASC,
+  CASE WHEN ? = '-date' THEN date END DESC,
+  CASE WHEN ? = 'name' THEN name END ASC,
+  CASE WHEN ? = '-name' THEN name END DESC,
+  id
+LIMIT ? OFFSET ?
 `
 
-type GetTopTimesRow struct {
-	ID          int32
-	Time        string
-	Place       int32
-	AthleteID   int32
-	AthleteName string
-	MeetID      int32
-	MeetName    string
-	MeetDate    time.Time
+type ListMeetsParams struct {
+	FromDate time.Time
+	ToDate   time.Time
+	Location string
+	Name     string
+	Sort     interface{}
+	Limit    int32
+	Offset   int32
 }
 
-func (q *Queries) GetTopTimes(ctx context.Context) ([]GetTopTimesRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTopTimes)
+func (q *Queries) ListMeets(ctx context.Context, arg ListMeetsParams) ([]Meet, error) {
+	rows, err := q.db.QueryContext(ctx, listMeets,
+		arg.FromDate,
+		arg.ToDate,
+		arg.Location,
+		arg.Name,
+		arg.Sort,
+		arg.Sort,
+		arg.Sort,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTopTimesRow
+	var items []Meet
 	for rows.Next() {
-		var i GetTopTimesRow
+		var i Meet
 		if err := rows.Scan(
 			&i.ID,
-			&i.Time,
-			&i.Place,
-			&i.AthleteID,
-			&i.AthleteName,
-			&i.MeetID,
-			&i.MeetName,
-			&i.MeetDate,
+			&i.Name,
+			&i.Date,
+			&i.Location,
+			&i.CourseID,
+			&i.SeasonID,
+			&i.Description,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -329,34 +2799,98 @@ func (q *Queries) GetTopTimes(ctx context.Context) ([]GetTopTimesRow, error) {
 	return items, nil
 }
 
+const promoteActiveAthletes = `-- name: PromoteActiveAthletes :execrows
+UPDATE athletes
+SET grade = grade + 1
+WHERE status = 'active' AND school_id IN (SELECT id FROM schools WHERE is_home)
+`
+
+func (q *Queries) PromoteActiveAthletes(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, promoteActiveAthletes)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const removeRosterMember = `-- name: RemoveRosterMember :exec
+DELETE FROM season_rosters WHERE season_id = ? AND athlete_id = ?
+`
+
+type RemoveRosterMemberParams struct {
+	SeasonID  int32
+	AthleteID int32
+}
+
+func (q *Queries) RemoveRosterMember(ctx context.Context, arg RemoveRosterMemberParams) error {
+	_, err := q.db.ExecContext(ctx, removeRosterMember,
+		arg.SeasonID,
+		arg.AthleteID,
+	)
+	return err
+}
+
 const updateAthlete = `-- name: UpdateAthlete :exec
 UPDATE athletes
-SET name = ?, grade = ?, personal_record = ?, events = ?
+SET name = ?, grade = ?, division = ?, school_id = ?, events = ?
 WHERE id = ?
 `
 
 type UpdateAthleteParams struct {
-	Name           string
-	Grade          int32
-	PersonalRecord sql.NullString
-	Events         sql.NullString
-	ID             int32
+	Name     string
+	Grade    int32
+	Division string
+	SchoolID int32
+	Events   sql.NullString
+	ID       int32
 }
 
 func (q *Queries) UpdateAthlete(ctx context.Context, arg UpdateAthleteParams) error {
 	_, err := q.db.ExecContext(ctx, updateAthlete,
 		arg.Name,
 		arg.Grade,
-		arg.PersonalRecord,
+		arg.Division,
+		arg.SchoolID,
 		arg.Events,
 		arg.ID,
 	)
 	return err
 }
 
+const updateCourse = `-- name: UpdateCourse :exec
+UPDATE courses
+SET name = ?, venue = ?, distance_meters = ?, surface = ?, latitude = ?, longitude = ?, notes = ?
+WHERE id = ?
+`
+
+type UpdateCourseParams struct {
+	Name           string
+	Venue          string
+	DistanceMeters int32
+	Surface        sql.NullString
+	Latitude       sql.NullFloat64
+	Longitude      sql.NullFloat64
+	Notes          sql.NullString
+	ID             int32
+}
+
+func (q *Queries) UpdateCourse(ctx context.Context, arg UpdateCourseParams) error {
+	_, err := q.db.ExecContext(ctx, updateCourse,
+		arg.Name,
+		arg.Venue,
+		arg.DistanceMeters,
+		arg.Surface,
+		arg.Latitude,
+		arg.Longitude,
+		arg.Notes,
+		arg.ID,
+	)
+	return err
+}
+
 const updateMeet = `-- name: UpdateMeet :exec
 UPDATE meets
-SET name = ?, date = ?, location = ?, description = ?
+SET name = ?, date = ?, location = ?, course_id = ?, season_id = ?, description = ?
 WHERE id = ?
 `
 
@@ -364,6 +2898,8 @@ type UpdateMeetParams struct {
 	Name        string
 	Date        time.Time
 	Location    string
+	CourseID    sql.NullInt32
+	SeasonID    sql.NullInt32
 	Description sql.NullString
 	ID          int32
 }
@@ -373,23 +2909,52 @@ func (q *Queries) UpdateMeet(ctx context.Context, arg UpdateMeetParams) error {
 		arg.Name,
 		arg.Date,
 		arg.Location,
+		arg.CourseID,
+		arg.SeasonID,
 		arg.Description,
 		arg.ID,
 	)
 	return err
 }
 
+const updateRace = `-- name: UpdateRace :exec
+UPDATE races
+SET name = ?, distance_meters = ?, entry_limit = ?, start_time = ?
+WHERE id = ?
+`
+
+type UpdateRaceParams struct {
+	Name           string
+	DistanceMeters int32
+	EntryLimit     sql.NullInt32
+	StartTime      sql.NullTime
+	ID             int32
+}
+
+func (q *Queries) UpdateRace(ctx context.Context, arg UpdateRaceParams) error {
+	_, err := q.db.ExecContext(ctx, updateRace,
+		arg.Name,
+		arg.DistanceMeters,
+		arg.EntryLimit,
+		arg.StartTime,
+		arg.ID,
+	)
+	return err
+}
+
 const updateResult = `-- name: UpdateResult :exec
 UPDATE results
-SET athlete_id = ?, meet_id = ?, time = ?, place = ?
+SET athlete_id = ?, meet_id = ?, race_id = ?, status = ?, time_ms = ?, place = ?
 WHERE id = ?
 `
 
 type UpdateResultParams struct {
 	AthleteID int32
 	MeetID    int32
-	Time      string
-	Place     int32
+	RaceID    int32
+	Status    string
+	TimeMs    sql.NullInt32
+	Place     sql.NullInt32
 	ID        int32
 }
 
@@ -397,9 +2962,139 @@ func (q *Queries) UpdateResult(ctx context.Context, arg UpdateResultParams) erro
 	_, err := q.db.ExecContext(ctx, updateResult,
 		arg.AthleteID,
 		arg.MeetID,
-		arg.Time,
+		arg.RaceID,
+		arg.Status,
+		arg.TimeMs,
 		arg.Place,
 		arg.ID,
 	)
 	return err
 }
+
+const updateSchool = `-- name: UpdateSchool :exec
+UPDATE schools
+SET name = ?
+WHERE id = ?
+`
+
+type UpdateSchoolParams struct {
+	Name string
+	ID   int32
+}
+
+func (q *Queries) UpdateSchool(ctx context.Context, arg UpdateSchoolParams) error {
+	_, err := q.db.ExecContext(ctx, updateSchool,
+		arg.Name,
+		arg.ID,
+	)
+	return err
+}
+
+const updateSeason = `-- name: UpdateSeason :exec
+UPDATE seasons
+SET name = ?, year = ?, start_date = ?, end_date = ?
+WHERE id = ?
+`
+
+type UpdateSeasonParams struct {
+	Name      string
+	Year      int32
+	StartDate time.Time
+	EndDate   time.Time
+	ID        int32
+}
+
+func (q *Queries) UpdateSeason(ctx context.Context, arg UpdateSeasonParams) error {
+	_, err := q.db.ExecContext(ctx, updateSeason,
+		arg.Name,
+		arg.Year,
+		arg.StartDate,
+		arg.EndDate,
+		arg.ID,
+	)
+	return err
+}
+
+const updateTimingTapTime = `-- name: UpdateTimingTapTime :exec
+UPDATE timing_taps SET elapsed_ms = ? WHERE id = ? AND session_id = ?
+`
+
+type UpdateTimingTapTimeParams struct {
+	ElapsedMs int32
+	ID        int32
+	SessionID int32
+}
+
+func (q *Queries) UpdateTimingTapTime(ctx context.Context, arg UpdateTimingTapTimeParams) error {
+	_, err := q.db.ExecContext(ctx, updateTimingTapTime,
+		arg.ElapsedMs,
+		arg.ID,
+		arg.SessionID,
+	)
+	return err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = ? WHERE id = ?
+`
+
+type UpdateUserPasswordParams struct {
+	PasswordHash string
+	ID           int32
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPassword,
+		arg.PasswordHash,
+		arg.ID,
+	)
+	return err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :exec
+UPDATE users SET role = ? WHERE id = ?
+`
+
+type UpdateUserRoleParams struct {
+	Role string
+	ID   int32
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserRole,
+		arg.Role,
+		arg.ID,
+	)
+	return err
+}
+
+const updateWorkout = `-- name: UpdateWorkout :exec
+UPDATE workouts
+SET date = ?, type = ?, distance_meters = ?, duration_ms = ?, effort = ?, notes = ?
+WHERE id = ? AND athlete_id = ?
+`
+
+type UpdateWorkoutParams struct {
+	Date           time.Time
+	Type           string
+	DistanceMeters int32
+	DurationMs     sql.NullInt32
+	Effort         sql.NullInt32
+	Notes          sql.NullString
+	ID             int32
+	AthleteID      int32
+}
+
+func (q *Queries) UpdateWorkout(ctx context.Context, arg UpdateWorkoutParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkout,
+		arg.Date,
+		arg.Type,
+		arg.DistanceMeters,
+		arg.DurationMs,
+		arg.Effort,
+		arg.Notes,
+		arg.ID,
+		arg.AthleteID,
+	)
+	return err
+}
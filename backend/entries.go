@@ -0,0 +1,426 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EntryRequest enters an athlete in a race, optionally with the bib number
+// they wear at the meet. The race may be omitted when the meet has exactly
+// one.
+type EntryRequest struct {
+	AthleteID int32 `json:"athleteId" binding:"required"`
+	RaceID    int32 `json:"raceId"`
+	Bib       int32 `json:"bib" binding:"omitempty,gt=0"`
+}
+
+// DeclareEntriesRequest replaces a meet's whole lineup. An empty list clears
+// it.
+type DeclareEntriesRequest struct {
+	Entries []EntryRequest `json:"entries" binding:"required,dive"`
+}
+
+type EntryResponse struct {
+	ID              int32  `json:"id"`
+	Bib             int32  `json:"bib"`
+	AthleteID       int32  `json:"athleteId"`
+	AthleteName     string `json:"athleteName"`
+	AthleteGrade    int32  `json:"athleteGrade"`
+	AthleteDivision string `json:"athleteDivision"`
+}
+
+// RaceEntriesResponse lists who is entered in one race of a meet. EntryLimit
+// is zero when the race has no limit.
+type RaceEntriesResponse struct {
+	RaceID     int32           `json:"raceId"`
+	RaceName   string          `json:"raceName"`
+	EntryLimit int32           `json:"entryLimit"`
+	Entries    []EntryResponse `json:"entries"`
+}
+
+// UnenteredResultResponse is a result for an athlete who was not entered in
+// the race they ran. EnteredRaceID is the race they were entered in instead,
+// or zero when they were not entered in the meet at all.
+type UnenteredResultResponse struct {
+	MeetResultResponse
+	EnteredRaceID int32 `json:"enteredRaceId"`
+}
+
+func nullEntryLimit(limit int32) sql.NullInt32 {
+	return sql.NullInt32{Int32: limit, Valid: limit > 0}
+}
+
+// checkEntryLimit makes sure a race is not given a limit below the number of
+// athletes already entered in it.
+func checkEntryLimit(ctx context.Context, raceID, limit int32) error {
+	if limit == 0 {
+		return nil
+	}
+	race, err := queries.GetRaceByID(ctx, raceID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	entries, err := queries.GetMeetEntries(ctx, race.MeetID)
+	if err != nil {
+		return err
+	}
+	entered := 0
+	for _, e := range entries {
+		if e.RaceID == race.ID {
+			entered++
+		}
+	}
+	if int32(entered) > limit {
+		return badRequest(fmt.Sprintf("%s already has %d entries", race.Name, entered))
+	}
+	return nil
+}
+
+// validateEntries checks a meet's lineup before it is saved: every athlete
+// must exist, be one of our active athletes, be entered only once with a bib
+// of their own, and run in one of the meet's races without the race going
+// over its entry limit.
+func validateEntries(ctx context.Context, q *db.Queries, meetID int32, entries []EntryRequest) ([]db.CreateEntryParams, error) {
+	home, err := q.GetHomeSchool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	athletes, err := q.GetAllAthletes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int32]db.Athlete, len(athletes))
+	for _, a := range athletes {
+		known[a.ID] = a
+	}
+
+	races, err := q.GetRacesByMeetID(ctx, meetID)
+	if err != nil {
+		return nil, err
+	}
+	meetRaces := make(map[int32]db.Race, len(races))
+	for _, r := range races {
+		meetRaces[r.ID] = r
+	}
+
+	params := make([]db.CreateEntryParams, len(entries))
+	entered := make(map[int32]bool)
+	bibs := make(map[int32]bool)
+	counts := make(map[int32]int32)
+	for i, e := range entries {
+		athlete, ok := known[e.AthleteID]
+		if !ok {
+			return nil, badRequest(fmt.Sprintf("Athlete %d not found", e.AthleteID))
+		}
+		if athlete.SchoolID != home.ID {
+			return nil, badRequest(fmt.Sprintf("%s does not run for %s", athlete.Name, home.Name))
+		}
+		if athlete.Status != athleteActive {
+			return nil, badRequest(fmt.Sprintf("%s is not an active athlete", athlete.Name))
+		}
+		if entered[athlete.ID] {
+			return nil, badRequest(fmt.Sprintf("%s is entered more than once", athlete.Name))
+		}
+		entered[athlete.ID] = true
+		if e.Bib > 0 {
+			if bibs[e.Bib] {
+				return nil, badRequest(fmt.Sprintf("Bib %d is given to more than one athlete", e.Bib))
+			}
+			bibs[e.Bib] = true
+		}
+
+		raceID := e.RaceID
+		if raceID == 0 {
+			if len(races) != 1 {
+				return nil, badRequest("raceId is required unless the meet has exactly one race")
+			}
+			raceID = races[0].ID
+		}
+		race, ok := meetRaces[raceID]
+		if !ok {
+			return nil, badRequest(fmt.Sprintf("Race %d does not belong to this meet", raceID))
+		}
+		counts[race.ID]++
+		if race.EntryLimit.Valid && counts[race.ID] > race.EntryLimit.Int32 {
+			return nil, badRequest(fmt.Sprintf("%s is limited to %d entries", race.Name, race.EntryLimit.Int32))
+		}
+
+		params[i] = db.CreateEntryParams{
+			MeetID:    meetID,
+			RaceID:    race.ID,
+			AthleteID: athlete.ID,
+			Bib:       sql.NullInt32{Int32: e.Bib, Valid: e.Bib > 0},
+		}
+	}
+	return params, nil
+}
+
+// validateEntry checks one athlete being added to a meet whose other entries
+// stand as they are. On a transaction the race is locked while its entries
+// are counted, so two coaches cannot both take its last place.
+func validateEntry(ctx context.Context, q *db.Queries, meetID int32, entry EntryRequest) (db.CreateEntryParams, error) {
+	params, err := validateEntries(ctx, q, meetID, []EntryRequest{entry})
+	if err != nil {
+		return db.CreateEntryParams{}, err
+	}
+	p := params[0]
+
+	if p.Bib.Valid {
+		other, err := q.GetEntryByBib(ctx, db.GetEntryByBibParams{MeetID: meetID, Bib: p.Bib})
+		if err == nil && other.AthleteID != p.AthleteID {
+			return db.CreateEntryParams{}, badRequest(fmt.Sprintf("Bib %d is already worn by %s", p.Bib.Int32, other.AthleteName))
+		} else if err != nil && err != sql.ErrNoRows {
+			return db.CreateEntryParams{}, err
+		}
+	}
+
+	race, err := q.GetRaceForUpdate(ctx, p.RaceID)
+	if err != nil {
+		return db.CreateEntryParams{}, err
+	}
+	if race.EntryLimit.Valid {
+		entered, err := q.CountOtherRaceEntries(ctx, db.CountOtherRaceEntriesParams{
+			RaceID:    race.ID,
+			AthleteID: p.AthleteID,
+		})
+		if err != nil {
+			return db.CreateEntryParams{}, err
+		}
+		if entered >= int64(race.EntryLimit.Int32) {
+			return db.CreateEntryParams{}, badRequest(fmt.Sprintf("%s is limited to %d entries", race.Name, race.EntryLimit.Int32))
+		}
+	}
+	return p, nil
+}
+
+// meetParam loads the meet named in a route, reporting a missing meet as
+// not found.
+func meetParam(c *gin.Context) (db.Meet, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meet ID"})
+		return db.Meet{}, false
+	}
+	meet, err := queries.GetMeetByID(context.Background(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Meet not found"})
+			return db.Meet{}, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return db.Meet{}, false
+	}
+	return meet, true
+}
+
+func registerEntryRoutes(r *gin.Engine) {
+	// Get a meet's entries, race by race
+	r.GET("/api/meets/:id/entries", func(c *gin.Context) {
+		meet, ok := meetParam(c)
+		if !ok {
+			return
+		}
+
+		races, err := queries.GetRacesByMeetID(context.Background(), meet.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		entries, err := queries.GetMeetEntries(context.Background(), meet.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		byRace := make(map[int32][]EntryResponse)
+		for _, e := range entries {
+			byRace[e.RaceID] = append(byRace[e.RaceID], EntryResponse{
+				ID:              e.ID,
+				Bib:             e.Bib.Int32,
+				AthleteID:       e.AthleteID,
+				AthleteName:     e.AthleteName,
+				AthleteGrade:    gradeAt(e.AthleteGrade, e.SeasonGrade),
+				AthleteDivision: e.AthleteDivision,
+			})
+		}
+
+		response := make([]RaceEntriesResponse, len(races))
+		for i, race := range races {
+			response[i] = RaceEntriesResponse{
+				RaceID:     race.ID,
+				RaceName:   race.Name,
+				EntryLimit: race.EntryLimit.Int32,
+				Entries:    byRace[race.ID],
+			}
+			if response[i].Entries == nil {
+				response[i].Entries = []EntryResponse{}
+			}
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Declare a meet's whole lineup, replacing any earlier entries
+	r.PUT("/api/meets/:id/entries", func(c *gin.Context) {
+		meet, ok := meetParam(c)
+		if !ok {
+			return
+		}
+
+		var req DeclareEntriesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		params, err := validateEntries(context.Background(), queries, meet.ID, req.Entries)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		tx, err := conn.BeginTx(context.Background(), nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback()
+		qtx := queries.WithTx(tx)
+
+		if err := qtx.DeleteMeetEntries(context.Background(), meet.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		for _, p := range params {
+			if _, err := qtx.CreateEntry(context.Background(), p); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("%d entries declared successfully", len(params))})
+	})
+
+	// Enter one athlete in a meet, moving them if they are already entered in
+	// another of its races
+	r.POST("/api/meets/:id/entries", func(c *gin.Context) {
+		meet, ok := meetParam(c)
+		if !ok {
+			return
+		}
+
+		var req EntryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tx, err := conn.BeginTx(context.Background(), nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback()
+		qtx := queries.WithTx(tx)
+
+		params, err := validateEntry(context.Background(), qtx, meet.ID, req)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		err = qtx.DeleteEntry(context.Background(), db.DeleteEntryParams{
+			MeetID:    meet.ID,
+			AthleteID: req.AthleteID,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		result, err := qtx.CreateEntry(context.Background(), params)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		id, _ := result.LastInsertId()
+		c.JSON(http.StatusCreated, gin.H{"id": id, "message": "Entry created successfully"})
+	})
+
+	// Withdraw an athlete's entry from a meet
+	r.DELETE("/api/meets/:id/entries/:athleteId", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meet ID"})
+			return
+		}
+		athleteID, err := strconv.Atoi(c.Param("athleteId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid athlete ID"})
+			return
+		}
+
+		err = queries.DeleteEntry(context.Background(), db.DeleteEntryParams{
+			MeetID:    int32(id),
+			AthleteID: int32(athleteID),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Entry deleted successfully"})
+	})
+
+	// Get the meet's results for athletes who were not entered in the race they
+	// ran, including athletes not entered in the meet at all
+	r.GET("/api/meets/:id/unentered-results", func(c *gin.Context) {
+		meet, ok := meetParam(c)
+		if !ok {
+			return
+		}
+
+		results, err := queries.GetUnenteredResults(context.Background(), meet.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]UnenteredResultResponse, len(results))
+		for i, r := range results {
+			response[i] = UnenteredResultResponse{
+				MeetResultResponse: MeetResultResponse{
+					ID:           r.ID,
+					Status:       r.Status,
+					Time:         RaceTime(r.TimeMs.Int32),
+					Place:        r.Place.Int32,
+					AthleteID:    r.AthleteID,
+					AthleteName:  r.AthleteName,
+					AthleteGrade: gradeAt(r.AthleteGrade, r.SeasonGrade),
+					SchoolID:     r.SchoolID,
+					School:       r.SchoolName,
+					RaceID:       r.RaceID,
+					RaceName:     r.RaceName,
+					Pacing:       newPacing(RaceTime(r.TimeMs.Int32), r.DistanceMeters),
+				},
+				EnteredRaceID: r.EnteredRaceID.Int32,
+			}
+		}
+		c.JSON(http.StatusOK, response)
+	})
+}
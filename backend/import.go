@@ -0,0 +1,730 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Import row statuses. Only matched and new rows are imported, along with
+// fuzzy ones when the import accepts them; the rest are reported for review.
+// New rows are opponents seen for the first time in a full-field import.
+const (
+	importMatched     = "matched"
+	importFuzzy       = "fuzzy"
+	importNew         = "new"
+	importAmbiguous   = "ambiguous"
+	importUnknown     = "unknown"
+	importInvalid     = "invalid"
+	importOtherSchool = "other-school"
+)
+
+// fuzzyMatchThreshold is the minimum name similarity for a fuzzy match, and
+// fuzzyMatchMargin how far the best candidate must lead the runner-up.
+const (
+	fuzzyMatchThreshold = 0.8
+	fuzzyMatchMargin    = 0.1
+)
+
+// importResultStatuses maps the codes timing software prints in place of a
+// time or place to result statuses.
+var importResultStatuses = map[string]string{
+	"DNF": statusDNF,
+	"DNS": statusDNS,
+	"DQ":  statusDQ,
+	"DSQ": statusDQ,
+}
+
+// importColumns lists the header names recognized for each field.
+var importColumns = map[string][]string{
+	"place":  {"place", "pl", "pos", "position", "overall"},
+	"name":   {"name", "athlete", "athlete name", "runner", "full name"},
+	"grade":  {"grade", "gr", "yr", "year", "class"},
+	"school": {"school", "team", "affiliation"},
+	"time":   {"time", "mark", "finish", "finish time", "final time", "result"},
+}
+
+type ImportOptions struct {
+	MeetID int32
+	RaceID int32
+	// School picks out our own rows: those whose school contains it,
+	// ignoring case. It defaults to the home school's name.
+	School string
+	// FullField also imports the other schools' rows, adding the schools
+	// and athletes not seen before.
+	FullField bool
+	// Division is given to new opponent athletes. By default it is taken
+	// from our own runners in the file.
+	Division string
+	DryRun   bool
+	// AcceptFuzzy imports rows whose name only nearly matches one of our
+	// athletes. Without it they are reported for review with the athlete
+	// they would go to.
+	AcceptFuzzy bool
+	// Columns overrides the header name used for a field.
+	Columns map[string]string
+}
+
+type ImportRow struct {
+	Line   int    `json:"line"`
+	Place  int32  `json:"place"`
+	Name   string `json:"name"`
+	Grade  int32  `json:"grade"`
+	School string `json:"school"`
+	Time   string `json:"time"`
+	// ResultStatus is the status the result is recorded with; Status is
+	// the outcome of importing the row.
+	ResultStatus string   `json:"resultStatus"`
+	Status       string   `json:"status"`
+	AthleteID    int32    `json:"athleteId,omitempty"`
+	AthleteName  string   `json:"athleteName,omitempty"`
+	Candidates   []string `json:"candidates,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	ResultID     int64    `json:"resultId,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun   bool        `json:"dryRun"`
+	Imported int         `json:"imported"`
+	Flagged  int         `json:"flagged"`
+	Rows     []ImportRow `json:"rows"`
+}
+
+// parseResultsCSV reads a timing-software export. The header row decides
+// which column holds each field; place, name and time are required.
+func parseResultsCSV(r io.Reader, overrides map[string]string) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, badRequest("CSV file is empty")
+		}
+		return nil, badRequest("Invalid CSV: " + err.Error())
+	}
+
+	index := make(map[string]int)
+	for field, aliases := range importColumns {
+		if name, ok := overrides[field]; ok && name != "" {
+			aliases = []string{name}
+		}
+		for i, h := range header {
+			h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+			for _, alias := range aliases {
+				if h == strings.ToLower(alias) {
+					index[field] = i
+				}
+			}
+		}
+	}
+	for _, field := range []string{"place", "name", "time"} {
+		if _, ok := index[field]; !ok {
+			return nil, badRequest(fmt.Sprintf("CSV has no %s column", field))
+		}
+	}
+
+	get := func(record []string, field string) string {
+		i, ok := index[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, badRequest("Invalid CSV: " + err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+		row := ImportRow{
+			Line:   line,
+			Name:   get(record, "name"),
+			School: get(record, "school"),
+			Time:   get(record, "time"),
+		}
+		if row.Name == "" && row.Time == "" {
+			continue
+		}
+		placeField := get(record, "place")
+		row.ResultStatus = statusFinished
+		for _, code := range []string{row.Time, placeField} {
+			if status, ok := importResultStatuses[strings.ToUpper(code)]; ok {
+				row.ResultStatus = status
+			}
+		}
+		if row.ResultStatus == statusFinished {
+			place, err := strconv.Atoi(strings.TrimRight(placeField, "."))
+			if err != nil || place <= 0 {
+				row.Status = importInvalid
+				row.Error = "Invalid place"
+			}
+			row.Place = int32(place)
+		} else {
+			row.Time = ""
+		}
+		if grade, err := strconv.Atoi(get(record, "grade")); err == nil {
+			row.Grade = int32(grade)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// normalizeName lowercases a name, turns "Last, First" into "first last" and
+// drops punctuation.
+func normalizeName(name string) string {
+	if last, first, ok := strings.Cut(name, ","); ok {
+		name = first + " " + last
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-':
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// nameSimilarity scores two normalized names from 0 to 1, ignoring word order.
+func nameSimilarity(a, b string) float64 {
+	score := stringSimilarity(a, b)
+	sortedA, sortedB := strings.Fields(a), strings.Fields(b)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	if s := stringSimilarity(strings.Join(sortedA, " "), strings.Join(sortedB, " ")); s > score {
+		score = s
+	}
+	return score
+}
+
+// stringSimilarity is one minus the edit distance relative to the longer
+// string.
+func stringSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
+
+// matchAthlete finds the roster athlete a CSV name refers to. Grade, when the
+// file has one, breaks ties between equally good candidates.
+func matchAthlete(row *ImportRow, athletes []db.Athlete) {
+	type candidate struct {
+		athlete db.Athlete
+		score   float64
+	}
+	name := normalizeName(row.Name)
+	var candidates []candidate
+	for _, a := range athletes {
+		if score := nameSimilarity(name, normalizeName(a.Name)); score >= fuzzyMatchThreshold {
+			candidates = append(candidates, candidate{a, score})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	if len(candidates) == 0 {
+		row.Status = importUnknown
+		return
+	}
+
+	// Keep every candidate that is too close to the best to tell apart.
+	best := []candidate{candidates[0]}
+	for _, c := range candidates[1:] {
+		if candidates[0].score-c.score < fuzzyMatchMargin {
+			best = append(best, c)
+		}
+	}
+	if len(best) > 1 && row.Grade != 0 {
+		var sameGrade []candidate
+		for _, c := range best {
+			if c.athlete.Grade == row.Grade {
+				sameGrade = append(sameGrade, c)
+			}
+		}
+		if len(sameGrade) > 0 {
+			best = sameGrade
+		}
+	}
+
+	if len(best) > 1 {
+		row.Status = importAmbiguous
+		for _, c := range best {
+			row.Candidates = append(row.Candidates, c.athlete.Name)
+		}
+		return
+	}
+
+	row.AthleteID = best[0].athlete.ID
+	row.AthleteName = best[0].athlete.Name
+	row.Status = importMatched
+	if best[0].score < 1 {
+		row.Status = importFuzzy
+	}
+}
+
+// matchOpponents finds the athletes other schools' rows refer to. Opponents
+// are matched on their exact name within their school; those not seen before
+// are added on q, along with their school, unless this is a dry run. It
+// returns the schools it added.
+func matchOpponents(ctx context.Context, q *db.Queries, rows []ImportRow, indexes []int, division string, dryRun bool) ([]int32, error) {
+	type opponentSchool struct {
+		id     int32
+		roster []db.Athlete
+	}
+	schools := make(map[string]*opponentSchool)
+	var added []int32
+
+	for _, i := range indexes {
+		row := &rows[i]
+		key := strings.ToLower(row.School)
+		school, ok := schools[key]
+		if !ok {
+			school = &opponentSchool{}
+			schools[key] = school
+			s, err := q.GetSchoolByName(ctx, row.School)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			if err == nil {
+				school.id = s.ID
+				if school.roster, err = q.GetSchoolAthletes(ctx, s.ID); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		name := normalizeName(row.Name)
+		var found []db.Athlete
+		for _, a := range school.roster {
+			if normalizeName(a.Name) == name && (division == "" || a.Division == division) {
+				found = append(found, a)
+			}
+		}
+		switch {
+		case len(found) == 1:
+			row.AthleteID = found[0].ID
+			row.AthleteName = found[0].Name
+			row.Status = importMatched
+			continue
+		case len(found) > 1:
+			row.Status = importAmbiguous
+			for _, a := range found {
+				row.Candidates = append(row.Candidates, a.Name)
+			}
+			continue
+		case division == "":
+			row.Status = importInvalid
+			row.Error = "Division of new athlete is unknown"
+			continue
+		}
+
+		row.Status = importNew
+		if dryRun {
+			continue
+		}
+		if school.id == 0 {
+			result, err := q.CreateSchool(ctx, row.School)
+			if err != nil {
+				return nil, err
+			}
+			id, _ := result.LastInsertId()
+			school.id = int32(id)
+			added = append(added, school.id)
+		}
+		result, err := q.CreateAthlete(ctx, db.CreateAthleteParams{
+			Name:     row.Name,
+			Grade:    row.Grade,
+			Division: division,
+			SchoolID: school.id,
+		})
+		if err != nil {
+			return nil, err
+		}
+		id, _ := result.LastInsertId()
+		athlete := db.Athlete{ID: int32(id), Name: row.Name, Grade: row.Grade, Division: division, SchoolID: school.id}
+		school.roster = append(school.roster, athlete)
+		row.AthleteID = athlete.ID
+		row.AthleteName = athlete.Name
+	}
+	return added, nil
+}
+
+// importResults matches a CSV of meet results against the roster and records
+// the confident matches through the same validation and insert path as bulk
+// entry. A full-field import also records the other schools' runners. Every
+// write happens in one transaction; in a dry run nothing is written.
+func importResults(ctx context.Context, r io.Reader, opts ImportOptions) (ImportReport, error) {
+	report := ImportReport{DryRun: opts.DryRun}
+
+	if _, err := queries.GetMeetByID(ctx, opts.MeetID); err != nil {
+		if err == sql.ErrNoRows {
+			return report, badRequest("Meet not found")
+		}
+		return report, err
+	}
+
+	if opts.Division != "" && !slices.Contains(divisions, opts.Division) {
+		return report, badRequest("Invalid division, use boys or girls")
+	}
+
+	rows, err := parseResultsCSV(r, opts.Columns)
+	if err != nil {
+		return report, err
+	}
+
+	q := queries
+	var tx *sql.Tx
+	if !opts.DryRun {
+		if tx, err = conn.BeginTx(ctx, nil); err != nil {
+			return report, err
+		}
+		defer tx.Rollback()
+		q = queries.WithTx(tx)
+	}
+
+	home, err := q.GetHomeSchool(ctx)
+	if err != nil {
+		return report, err
+	}
+	athletes, err := q.GetSchoolAthletes(ctx, home.ID)
+	if err != nil {
+		return report, err
+	}
+
+	school := opts.School
+	if school == "" {
+		school = home.Name
+	}
+	school = strings.ToLower(school)
+
+	var bulk BulkResultsRequest
+	var bulkRows []int
+	addRow := func(i int) {
+		row := rows[i]
+		bulk.Results = append(bulk.Results, BulkResultRow{
+			AthleteID: row.AthleteID,
+			RaceID:    opts.RaceID,
+			Status:    row.ResultStatus,
+			Time:      row.Time,
+			Place:     row.Place,
+		})
+		bulkRows = append(bulkRows, i)
+	}
+
+	// Our own runners' division is given to new opponents in the same race.
+	ourDivisions := make(map[string]bool)
+	var opponents []int
+	for i := range rows {
+		row := &rows[i]
+		if row.Status == importInvalid {
+			continue
+		}
+		if row.School != "" && !strings.Contains(strings.ToLower(row.School), school) {
+			row.Status = importOtherSchool
+			if opts.FullField {
+				opponents = append(opponents, i)
+			}
+			continue
+		}
+		matchAthlete(row, athletes)
+		if row.Status == importMatched || (row.Status == importFuzzy && opts.AcceptFuzzy) {
+			for _, a := range athletes {
+				if a.ID == row.AthleteID {
+					ourDivisions[a.Division] = true
+				}
+			}
+			addRow(i)
+		}
+	}
+
+	var addedSchools []int32
+	addedAthletes := make(map[int]bool)
+	if len(opponents) > 0 {
+		division := opts.Division
+		if division == "" && len(ourDivisions) == 1 {
+			for d := range ourDivisions {
+				division = d
+			}
+		}
+		if addedSchools, err = matchOpponents(ctx, q, rows, opponents, division, opts.DryRun); err != nil {
+			return report, err
+		}
+		for _, i := range opponents {
+			if rows[i].AthleteID != 0 {
+				addedAthletes[i] = rows[i].Status == importNew
+				addRow(i)
+			}
+		}
+	}
+
+	requests, rowErrors, err := validateBulkResults(ctx, q, opts.MeetID, bulk)
+	if err != nil {
+		return report, err
+	}
+	rejected := make(map[int]bool)
+	for _, e := range rowErrors {
+		row := &rows[bulkRows[e.Row-1]]
+		row.Status = importInvalid
+		row.Error = e.Error
+		rejected[e.Row-1] = true
+	}
+
+	var valid []CreateResultRequest
+	var validRows []int
+	keep := make(map[int32]bool)
+	for i, req := range requests {
+		if !rejected[i] {
+			valid = append(valid, req)
+			validRows = append(validRows, bulkRows[i])
+			keep[req.AthleteID] = true
+		}
+	}
+
+	// Opponents added for rows that were then rejected are dropped again,
+	// along with schools that were added only for them.
+	dropped := make(map[int32]bool)
+	for i, added := range addedAthletes {
+		if id := rows[i].AthleteID; added && !keep[id] {
+			if err := q.DeleteAthlete(ctx, id); err != nil {
+				return report, err
+			}
+			dropped[id] = true
+		}
+	}
+	for i := range rows {
+		if dropped[rows[i].AthleteID] {
+			rows[i].AthleteID = 0
+			rows[i].AthleteName = ""
+		}
+	}
+	for _, id := range addedSchools {
+		count, err := q.CountSchoolAthletes(ctx, id)
+		if err != nil {
+			return report, err
+		}
+		if count == 0 {
+			if err := q.DeleteSchool(ctx, id); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	report.Imported = len(valid)
+	if opts.DryRun {
+		// New opponents only get an athlete when the import is saved, so
+		// their rows are counted without being validated.
+		for _, row := range rows {
+			if row.Status == importNew && row.AthleteID == 0 {
+				report.Imported++
+			}
+		}
+	}
+
+	if !opts.DryRun {
+		created, err := insertResults(ctx, q, valid)
+		if err != nil {
+			return report, err
+		}
+		if err := tx.Commit(); err != nil {
+			return report, err
+		}
+		publishCreated(ctx, created)
+		for i, cr := range created {
+			rows[validRows[i]].ResultID = cr.ID
+		}
+	}
+
+	for _, row := range rows {
+		switch row.Status {
+		case importAmbiguous, importUnknown, importInvalid:
+			report.Flagged++
+		case importFuzzy:
+			if !opts.AcceptFuzzy {
+				report.Flagged++
+			}
+		}
+	}
+	report.Rows = rows
+	if report.Rows == nil {
+		report.Rows = []ImportRow{}
+	}
+	return report, nil
+}
+
+func registerImportRoutes(r *gin.Engine) {
+	// Import a meet's results from a CSV file, sent either as the "file" form
+	// field or as the request body
+	r.POST("/api/meets/:id/results/import", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meet ID"})
+			return
+		}
+
+		opts := ImportOptions{
+			MeetID:      int32(id),
+			School:      c.Query("school"),
+			FullField:   c.Query("fullField") == "true",
+			Division:    c.Query("division"),
+			DryRun:      c.Query("dryRun") == "true",
+			AcceptFuzzy: c.Query("acceptFuzzy") == "true",
+			Columns:     make(map[string]string),
+		}
+		if raceID := c.Query("raceId"); raceID != "" {
+			rid, err := strconv.Atoi(raceID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid race ID"})
+				return
+			}
+			opts.RaceID = int32(rid)
+		}
+		for field := range importColumns {
+			if name := c.Query(field + "Column"); name != "" {
+				opts.Columns[field] = name
+			}
+		}
+
+		var body io.Reader = c.Request.Body
+		if strings.HasPrefix(c.ContentType(), "multipart/") {
+			header, err := c.FormFile("file")
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Missing CSV file"})
+				return
+			}
+			file, err := header.Open()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			defer file.Close()
+			body = file
+		}
+
+		report, err := importResults(context.Background(), body, opts)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		status := http.StatusCreated
+		if opts.DryRun {
+			status = http.StatusOK
+		}
+		c.JSON(status, report)
+	})
+}
+
+// runImportCommand implements the import-results subcommand:
+//
+//	server import-results -meet 3 [-race 5] [-school "Jones County"] [-full-field [-division boys]] [-accept-fuzzy] [-dry-run] results.csv
+func runImportCommand(args []string) {
+	fs := flag.NewFlagSet("import-results", flag.ExitOnError)
+	meetID := fs.Int("meet", 0, "meet to import results into")
+	raceID := fs.Int("race", 0, "race within the meet (optional when the meet has one race)")
+	school := fs.String("school", "", "name of our school in the file (default the home school)")
+	fullField := fs.Bool("full-field", false, "also import other schools' runners")
+	division := fs.String("division", "", "division of new opponent athletes (default that of our runners)")
+	acceptFuzzy := fs.Bool("accept-fuzzy", false, "also import rows whose name only nearly matches an athlete")
+	dryRun := fs.Bool("dry-run", false, "show what would be imported without saving")
+	fs.Parse(args)
+
+	if *meetID == 0 || fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: server import-results -meet ID [-race ID] [-school NAME] [-full-field [-division DIV]] [-accept-fuzzy] [-dry-run] FILE.csv")
+		os.Exit(2)
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	report, err := importResults(context.Background(), file, ImportOptions{
+		MeetID:      int32(*meetID),
+		RaceID:      int32(*raceID),
+		School:      *school,
+		FullField:   *fullField,
+		Division:    *division,
+		DryRun:      *dryRun,
+		AcceptFuzzy: *acceptFuzzy,
+	})
+	if err != nil {
+		var reqErr *requestError
+		if errors.As(err, &reqErr) {
+			fmt.Fprintln(os.Stderr, "import failed:", err)
+			os.Exit(1)
+		}
+		log.Fatal(err)
+	}
+
+	for _, row := range report.Rows {
+		if row.Status == importOtherSchool {
+			continue
+		}
+		detail := row.AthleteName
+		switch {
+		case row.Error != "":
+			detail = row.Error
+		case len(row.Candidates) > 0:
+			detail = "could be " + strings.Join(row.Candidates, " or ")
+		}
+		mark := row.Time
+		if row.ResultStatus != statusFinished {
+			mark = strings.ToUpper(row.ResultStatus)
+		}
+		fmt.Printf("line %-4d %-4d %-28s %-9s %-12s %s\n", row.Line, row.Place, row.Name, mark, row.Status, detail)
+	}
+
+	verb := "Imported"
+	if report.DryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d results, %d rows flagged for review\n", verb, report.Imported, report.Flagged)
+}
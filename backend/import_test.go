@@ -0,0 +1,70 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"jones-county-xc/backend/db"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Marcus Johnson", "marcus johnson"},
+		{"Johnson, Marcus", "marcus johnson"},
+		{"  JOHNSON ,  marcus ", "marcus johnson"},
+		{"Jake O'Neil", "jake oneil"},
+		{"Ava Brown-Lee", "ava brown lee"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeName(tt.in); got != tt.want {
+			t.Errorf("normalizeName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMatchAthlete(t *testing.T) {
+	roster := []db.Athlete{
+		{ID: 1, Name: "Marcus Johnson", Grade: 11},
+		{ID: 2, Name: "Tyler Smith", Grade: 10},
+		{ID: 3, Name: "Tyler Smyth", Grade: 12},
+		{ID: 4, Name: "Ava Brown", Grade: 9},
+		{ID: 5, Name: "Jake O'Neil", Grade: 10},
+	}
+	tests := []struct {
+		name       string
+		grade      int32
+		status     string
+		athleteID  int32
+		candidates []string
+	}{
+		{"Marcus Johnson", 0, importMatched, 1, nil},
+		{"Johnson, Marcus", 0, importMatched, 1, nil},
+		{"Johnson Marcus", 0, importMatched, 1, nil},
+		{"MARCUS JOHNSON", 0, importMatched, 1, nil},
+		{"Jake ONeil", 0, importMatched, 5, nil},
+		{"Marcus Jonson", 0, importFuzzy, 1, nil},
+		{"Ava Browne", 0, importFuzzy, 4, nil},
+		{"Tyler Smith", 0, importAmbiguous, 0, []string{"Tyler Smith", "Tyler Smyth"}},
+		{"Tyler Smith", 10, importMatched, 2, nil},
+		{"Tyler Smth", 12, importFuzzy, 3, nil},
+		{"Tyler Smth", 9, importAmbiguous, 0, []string{"Tyler Smith", "Tyler Smyth"}},
+		{"Zed Unknown", 0, importUnknown, 0, nil},
+		{"Tyler", 0, importUnknown, 0, nil},
+	}
+	for _, tt := range tests {
+		row := ImportRow{Name: tt.name, Grade: tt.grade}
+		matchAthlete(&row, roster)
+		if row.Status != tt.status || row.AthleteID != tt.athleteID {
+			t.Errorf("matchAthlete(%q, grade %d) = %s athlete %d, want %s athlete %d",
+				tt.name, tt.grade, row.Status, row.AthleteID, tt.status, tt.athleteID)
+		}
+		candidates := slices.Clone(row.Candidates)
+		slices.Sort(candidates)
+		if !slices.Equal(candidates, tt.candidates) {
+			t.Errorf("matchAthlete(%q, grade %d) candidates = %q, want %q", tt.name, tt.grade, row.Candidates, tt.candidates)
+		}
+	}
+}
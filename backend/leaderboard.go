@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"net/http"
+	"strconv"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+// rankTimes numbers a list sorted fastest first. Equal times share a rank and
+// the next slower time skips past them, so 1, 2, 2, 4.
+func rankTimes(times []TopTimeResponse) {
+	for i := range times {
+		if i > 0 && times[i].Time == times[i-1].Time {
+			times[i].Rank = times[i-1].Rank
+		} else {
+			times[i].Rank = int32(i + 1)
+		}
+	}
+}
+
+// bestPerAthlete keeps only each athlete's fastest mark from a list sorted
+// fastest first, stopping once limit marks are kept.
+func bestPerAthlete(times []TopTimeResponse, limit int) []TopTimeResponse {
+	seen := make(map[int32]bool)
+	best := []TopTimeResponse{}
+	for _, t := range times {
+		if len(best) == limit {
+			break
+		}
+		if !seen[t.AthleteID] {
+			seen[t.AthleteID] = true
+			best = append(best, t)
+		}
+	}
+	return best
+}
+
+// idQuery reads an optional ID query parameter.
+func idQuery(c *gin.Context, key, name string) (sql.NullInt32, error) {
+	value := c.Query(key)
+	if value == "" {
+		return sql.NullInt32{}, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return sql.NullInt32{}, badRequest("Invalid " + name + " ID")
+	}
+	return nullID(int32(id)), nil
+}
+
+func registerLeaderboardRoutes(r *gin.Engine) {
+	// Get the fastest times per division at a race distance (5000m by
+	// default) for the home school, or another with ?schoolId. Narrow the list
+	// with ?seasonId, ?courseId, ?from and ?to dates, ?grade and ?division,
+	// size it with ?limit, and pass ?bestOnly=true to list each athlete once
+	// with their best mark.
+	r.GET("/api/top-times", func(c *gin.Context) {
+		distance, err := strconv.Atoi(c.DefaultQuery("distance", strconv.Itoa(defaultDistanceMeters)))
+		if err != nil || distance <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid distance"})
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLeaderboardLimit)))
+		if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit, use 1 to " + strconv.Itoa(maxLeaderboardLimit)})
+			return
+		}
+
+		params := db.GetTopTimesParams{DistanceMeters: int32(distance), Limit: int32(limit)}
+		if params.SchoolID, err = schoolQuery(context.Background(), c); err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if params.SeasonID, err = idQuery(c, "seasonId", "season"); err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if params.CourseID, err = idQuery(c, "courseId", "course"); err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if params.FromDate, err = dateQuery(c, "from", earliestDate); err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if params.ToDate, err = dateQuery(c, "to", latestDate); err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if g := c.Query("grade"); g != "" {
+			grade, err := strconv.Atoi(g)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid grade"})
+				return
+			}
+			params.Grade = sql.NullInt32{Int32: int32(grade), Valid: true}
+		}
+
+		// Every mark is needed to find each athlete's best, so the limit is
+		// applied afterwards.
+		bestOnly := c.Query("bestOnly") == "true"
+		if bestOnly {
+			params.Limit = math.MaxInt32
+		}
+
+		selected, err := divisionsFromQuery(c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]DivisionTopTimesResponse, len(selected))
+		for d, division := range selected {
+			params.Division = division
+			times, err := queries.GetTopTimes(context.Background(), params)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+
+			list := make([]TopTimeResponse, len(times))
+			for i, t := range times {
+				list[i] = TopTimeResponse{
+					ID:          t.ID,
+					Time:        RaceTime(t.TimeMs.Int32),
+					Place:       t.Place.Int32,
+					AthleteID:   t.AthleteID,
+					AthleteName: t.AthleteName,
+					MeetID:      t.MeetID,
+					MeetName:    t.MeetName,
+					MeetDate:    t.MeetDate.Format("2006-01-02"),
+					RaceID:      t.RaceID,
+					RaceName:    t.RaceName,
+					Pacing:      newPacing(RaceTime(t.TimeMs.Int32), int32(distance)),
+				}
+			}
+			if bestOnly {
+				list = bestPerAthlete(list, limit)
+			}
+			rankTimes(list)
+			response[d] = DivisionTopTimesResponse{Division: division, Times: list}
+		}
+		c.JSON(http.StatusOK, response)
+	})
+}
@@ -0,0 +1,461 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The default blend used to rank a lineup. Weights only matter relative to
+// each other.
+const (
+	defaultBestWeight        = 0.4
+	defaultFormWeight        = 0.3
+	defaultConsistencyWeight = 0.1
+	defaultHeadToHeadWeight  = 0.2
+)
+
+const (
+	defaultFormRaces  = 3
+	maxFormRaces      = 10
+	defaultAlternates = 3
+	maxAlternates     = 20
+	// neutralScore is given for a factor that can't be judged yet, such as
+	// consistency after a single race.
+	neutralScore = 0.5
+	// closeCallMargin is how many points apart, out of 100, the last varsity
+	// pick and the first alternate can be before the call is flagged as close.
+	closeCallMargin = 2
+)
+
+// LineupFactors holds one value per ranking factor: either the weights of
+// the blend or an athlete's score on each, from 0 (worst) to 1 (best).
+type LineupFactors struct {
+	SeasonBest  float64 `json:"seasonBest"`
+	RecentForm  float64 `json:"recentForm"`
+	Consistency float64 `json:"consistency"`
+	HeadToHead  float64 `json:"headToHead"`
+}
+
+// LineupPick is one athlete's place in the recommended lineup. Averages are
+// to the tenth of a second. Spread is the standard deviation of their times
+// and is blank with fewer than two races.
+type LineupPick struct {
+	Rank             int           `json:"rank"`
+	AthleteID        int32         `json:"athleteId"`
+	Name             string        `json:"name"`
+	Grade            int32         `json:"grade"`
+	Races            int           `json:"races"`
+	SeasonBest       RaceTime      `json:"seasonBest"`
+	RecentAverage    RaceTime      `json:"recentAverage"`
+	Spread           RaceTime      `json:"spread"`
+	HeadToHeadWins   int           `json:"headToHeadWins"`
+	HeadToHeadLosses int           `json:"headToHeadLosses"`
+	Scores           LineupFactors `json:"scores"`
+	Score            float64       `json:"score"`
+	Reasons          []string      `json:"reasons"`
+}
+
+// LineupResponse is the recommended varsity seven and their alternates.
+// Unranked athletes have not finished a race at the distance this season.
+type LineupResponse struct {
+	SeasonID       int32         `json:"seasonId"`
+	Division       string        `json:"division"`
+	DistanceMeters int32         `json:"distanceMeters"`
+	RecentRaces    int           `json:"recentRaces"`
+	Weights        LineupFactors `json:"weights"`
+	Varsity        []LineupPick  `json:"varsity"`
+	Alternates     []LineupPick  `json:"alternates"`
+	Unranked       []LineupPick  `json:"unranked"`
+}
+
+// lineupCandidate is a roster athlete with their results for the season.
+type lineupCandidate struct {
+	ID    int32
+	Name  string
+	Grade int32
+	Rows  []db.GetAthleteHistoryRow
+}
+
+// ordinal formats 1 as 1st, 2 as 2nd and so on.
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// lowerIsBetter scores values where smaller is better from 0 to 1 across the
+// field, along with each value's rank. Missing values are left out of both.
+func lowerIsBetter(values []float64, present []bool) ([]float64, []int) {
+	best, worst := math.Inf(1), math.Inf(-1)
+	for i, v := range values {
+		if present[i] {
+			best, worst = math.Min(best, v), math.Max(worst, v)
+		}
+	}
+	scores := make([]float64, len(values))
+	ranks := make([]int, len(values))
+	for i, v := range values {
+		if !present[i] {
+			continue
+		}
+		scores[i] = 1
+		if worst > best {
+			scores[i] = (worst - v) / (worst - best)
+		}
+		ranks[i] = 1
+		for j, other := range values {
+			if present[j] && other < v {
+				ranks[i]++
+			}
+		}
+	}
+	return scores, ranks
+}
+
+// rankLineup orders athletes by a weighted blend of their season best, the
+// average of their last formRaces races, how consistent their times are, and
+// their head-to-head record against the rest of the candidates. Only races at
+// distanceMeters count towards times; head-to-head meetings count at any
+// distance. Athletes without a time are returned separately.
+func rankLineup(candidates []lineupCandidate, distanceMeters int32, weights LineupFactors, formRaces int) ([]LineupPick, []LineupPick) {
+	var ranked []lineupCandidate
+	var unranked []LineupPick
+	for _, cand := range candidates {
+		finished := false
+		for _, r := range cand.Rows {
+			if r.Status == statusFinished && r.DistanceMeters == distanceMeters {
+				finished = true
+			}
+		}
+		if !finished {
+			unranked = append(unranked, LineupPick{
+				AthleteID: cand.ID,
+				Name:      cand.Name,
+				Grade:     cand.Grade,
+				Reasons:   []string{fmt.Sprintf("No finished %dm races this season", distanceMeters)},
+			})
+			continue
+		}
+		ranked = append(ranked, cand)
+	}
+
+	n := len(ranked)
+	picks := make([]LineupPick, n)
+	bests := make([]float64, n)
+	recents := make([]float64, n)
+	spreads := make([]float64, n)
+	hasSpread := make([]bool, n)
+	all := make([]bool, n)
+	recentCounts := make([]int, n)
+	for i, cand := range ranked {
+		var times []float64
+		for _, r := range cand.Rows {
+			if r.Status == statusFinished && r.DistanceMeters == distanceMeters {
+				times = append(times, float64(r.TimeMs.Int32))
+			}
+		}
+
+		bests[i] = slices.Min(times)
+		recent := times[max(0, len(times)-formRaces):]
+		recentCounts[i] = len(recent)
+		recents[i] = mean(recent)
+		if len(times) >= 2 {
+			avg := mean(times)
+			var sumSquares float64
+			for _, t := range times {
+				sumSquares += (t - avg) * (t - avg)
+			}
+			spreads[i] = math.Sqrt(sumSquares / float64(len(times)))
+			hasSpread[i] = true
+		}
+		all[i] = true
+
+		picks[i] = LineupPick{
+			AthleteID:     cand.ID,
+			Name:          cand.Name,
+			Grade:         cand.Grade,
+			Races:         len(times),
+			SeasonBest:    RaceTime(math.Round(bests[i])),
+			RecentAverage: RaceTime(math.Round(recents[i]/100) * 100),
+			Spread:        RaceTime(math.Round(spreads[i]/100) * 100),
+		}
+	}
+
+	for i := range ranked {
+		for j := i + 1; j < n; j++ {
+			for _, race := range headToHead(ranked[i].ID, ranked[i].Rows, ranked[j].ID, ranked[j].Rows) {
+				switch race.WinnerID {
+				case ranked[i].ID:
+					picks[i].HeadToHeadWins++
+					picks[j].HeadToHeadLosses++
+				case ranked[j].ID:
+					picks[j].HeadToHeadWins++
+					picks[i].HeadToHeadLosses++
+				}
+			}
+		}
+	}
+
+	bestScores, bestRanks := lowerIsBetter(bests, all)
+	recentScores, recentRanks := lowerIsBetter(recents, all)
+	spreadScores, spreadRanks := lowerIsBetter(spreads, hasSpread)
+	totalWeight := weights.SeasonBest + weights.RecentForm + weights.Consistency + weights.HeadToHead
+	for i := range picks {
+		p := &picks[i]
+		p.Scores = LineupFactors{
+			SeasonBest:  bestScores[i],
+			RecentForm:  recentScores[i],
+			Consistency: neutralScore,
+			HeadToHead:  neutralScore,
+		}
+		if hasSpread[i] {
+			p.Scores.Consistency = spreadScores[i]
+		}
+		meetings := p.HeadToHeadWins + p.HeadToHeadLosses
+		if meetings > 0 {
+			p.Scores.HeadToHead = float64(p.HeadToHeadWins) / float64(meetings)
+		}
+		blend := weights.SeasonBest*p.Scores.SeasonBest + weights.RecentForm*p.Scores.RecentForm +
+			weights.Consistency*p.Scores.Consistency + weights.HeadToHead*p.Scores.HeadToHead
+		p.Score = math.Round(blend/totalWeight*1000) / 10
+
+		p.Reasons = []string{fmt.Sprintf("Season best of %s, %s of %d", p.SeasonBest, ordinal(bestRanks[i]), n)}
+		if recentCounts[i] > 1 {
+			p.Reasons = append(p.Reasons, fmt.Sprintf("Averaging %s over the last %d races, %s of %d", p.RecentAverage, recentCounts[i], ordinal(recentRanks[i]), n))
+		} else {
+			p.Reasons = append(p.Reasons, fmt.Sprintf("Ran %s in the latest race, %s of %d", p.RecentAverage, ordinal(recentRanks[i]), n))
+		}
+		switch {
+		case hasSpread[i] && p.Spread == 0:
+			p.Reasons = append(p.Reasons, fmt.Sprintf("The same time in all %d races, %s for consistency", p.Races, ordinal(spreadRanks[i])))
+		case hasSpread[i]:
+			p.Reasons = append(p.Reasons, fmt.Sprintf("Times spread by %s over %d races, %s for consistency", p.Spread, p.Races, ordinal(spreadRanks[i])))
+		default:
+			p.Reasons = append(p.Reasons, "Only one race, so consistency is not yet known")
+		}
+		if meetings > 0 {
+			p.Reasons = append(p.Reasons, fmt.Sprintf("Won %d of %d head-to-head meetings with the other candidates", p.HeadToHeadWins, meetings))
+		} else {
+			p.Reasons = append(p.Reasons, "No head-to-head meetings with the other candidates")
+		}
+	}
+
+	sort.SliceStable(picks, func(i, j int) bool {
+		if picks[i].Score != picks[j].Score {
+			return picks[i].Score > picks[j].Score
+		}
+		if picks[i].SeasonBest != picks[j].SeasonBest {
+			return picks[i].SeasonBest < picks[j].SeasonBest
+		}
+		return picks[i].Name < picks[j].Name
+	})
+	for i := range picks {
+		picks[i].Rank = i + 1
+	}
+	return picks, unranked
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// weightQuery reads one of the lineup weights, which can't be negative.
+func weightQuery(c *gin.Context, key string, fallback float64) (float64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return fallback, nil
+	}
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil || weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+		return 0, badRequest("Invalid " + key)
+	}
+	return weight, nil
+}
+
+// countQuery reads an optional whole number between min and max.
+func countQuery(c *gin.Context, key string, fallback, min, max int) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, badRequest(fmt.Sprintf("Invalid %s, use %d to %d", key, min, max))
+	}
+	return n, nil
+}
+
+// lineupSeason returns the season named by ?seasonId, or else the current
+// season, or else the latest one.
+func lineupSeason(ctx context.Context, c *gin.Context) (db.Season, error) {
+	if s := c.Query("seasonId"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return db.Season{}, badRequest("Invalid season ID")
+		}
+		season, err := queries.GetSeasonByID(ctx, int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return db.Season{}, badRequest("Season not found")
+			}
+			return db.Season{}, err
+		}
+		return season, nil
+	}
+
+	today := time.Now()
+	season, err := queries.GetSeasonForDate(ctx, db.GetSeasonForDateParams{
+		StartDate: today,
+		EndDate:   today,
+	})
+	if err != sql.ErrNoRows {
+		return season, err
+	}
+	seasons, err := queries.GetAllSeasons(ctx)
+	if err != nil {
+		return db.Season{}, err
+	}
+	if len(seasons) == 0 {
+		return db.Season{}, badRequest("No seasons have been set up")
+	}
+	return seasons[0], nil
+}
+
+func registerLineupRoutes(r *gin.Engine) {
+	// Recommend a division's varsity seven and alternates from the season
+	// roster. The ranking blends season best, recent form over the last
+	// ?recentRaces, consistency and head-to-head record, weighted by
+	// ?bestWeight, ?formWeight, ?consistencyWeight and ?headToHeadWeight.
+	// Takes ?seasonId, ?distance and ?alternates.
+	r.GET("/api/lineup", func(c *gin.Context) {
+		ctx := context.Background()
+
+		selected, err := divisionsFromQuery(c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if len(selected) != 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Choose a division, boys or girls"})
+			return
+		}
+		division := selected[0]
+
+		season, err := lineupSeason(ctx, c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		distance, err := distanceQuery(c, "distance", defaultDistanceMeters)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		formRaces, err := countQuery(c, "recentRaces", defaultFormRaces, 1, maxFormRaces)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		alternates, err := countQuery(c, "alternates", defaultAlternates, 0, maxAlternates)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		var weights LineupFactors
+		for _, w := range []struct {
+			key      string
+			fallback float64
+			value    *float64
+		}{
+			{"bestWeight", defaultBestWeight, &weights.SeasonBest},
+			{"formWeight", defaultFormWeight, &weights.RecentForm},
+			{"consistencyWeight", defaultConsistencyWeight, &weights.Consistency},
+			{"headToHeadWeight", defaultHeadToHeadWeight, &weights.HeadToHead},
+		} {
+			if *w.value, err = weightQuery(c, w.key, w.fallback); err != nil {
+				c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if weights.SeasonBest+weights.RecentForm+weights.Consistency+weights.HeadToHead == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "At least one weight must be above zero"})
+			return
+		}
+
+		roster, err := queries.GetSeasonRoster(ctx, season.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		var candidates []lineupCandidate
+		for _, a := range roster {
+			if a.Division != division {
+				continue
+			}
+			rows, err := queries.GetAthleteHistory(ctx, a.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			candidates = append(candidates, lineupCandidate{
+				ID:    a.ID,
+				Name:  a.Name,
+				Grade: a.Grade,
+				Rows:  seasonRows(rows, nullID(season.ID)),
+			})
+		}
+
+		picks, unranked := rankLineup(candidates, distance, weights, formRaces)
+		varsity := picks[:min(teamDisplacers, len(picks))]
+		rest := picks[len(varsity):]
+		response := LineupResponse{
+			SeasonID:       season.ID,
+			Division:       division,
+			DistanceMeters: distance,
+			RecentRaces:    formRaces,
+			Weights:        weights,
+			Varsity:        varsity,
+			Alternates:     rest[:min(alternates, len(rest))],
+			Unranked:       unranked,
+		}
+		if len(rest) > 0 {
+			last, next := &response.Varsity[len(varsity)-1], rest[0]
+			if last.Score-next.Score < closeCallMargin {
+				last.Reasons = append(last.Reasons, fmt.Sprintf("Close call for the last varsity spot with %s", next.Name))
+				if len(response.Alternates) > 0 {
+					response.Alternates[0].Reasons = append(response.Alternates[0].Reasons, fmt.Sprintf("Close call for the last varsity spot with %s", last.Name))
+				}
+			}
+		}
+		for _, list := range []*[]LineupPick{&response.Varsity, &response.Alternates, &response.Unranked} {
+			if *list == nil {
+				*list = []LineupPick{}
+			}
+		}
+		c.JSON(http.StatusOK, response)
+	})
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"jones-county-xc/backend/db"
+)
+
+func TestOrdinal(t *testing.T) {
+	tests := map[int]string{
+		1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 10: "10th",
+		11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd",
+		101: "101st", 111: "111th", 112: "112th",
+	}
+	for n, want := range tests {
+		if got := ordinal(n); got != want {
+			t.Errorf("ordinal(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestLowerIsBetter(t *testing.T) {
+	scores, ranks := lowerIsBetter(
+		[]float64{10, 20, 15, 0, 10},
+		[]bool{true, true, true, false, true},
+	)
+	wantScores := []float64{1, 0, 0.5, 0, 1}
+	wantRanks := []int{1, 4, 3, 0, 1}
+	for i := range scores {
+		if math.Abs(scores[i]-wantScores[i]) > 1e-9 || ranks[i] != wantRanks[i] {
+			t.Errorf("value %d: score %v rank %d, want %v rank %d", i, scores[i], ranks[i], wantScores[i], wantRanks[i])
+		}
+	}
+
+	// A field where everyone is level scores everyone as the best.
+	scores, ranks = lowerIsBetter([]float64{5, 5}, []bool{true, true})
+	if scores[0] != 1 || scores[1] != 1 || ranks[0] != 1 || ranks[1] != 1 {
+		t.Errorf("level field scored %v ranked %v", scores, ranks)
+	}
+}
+
+func TestRankLineup(t *testing.T) {
+	shorter := historyRow(4, statusFinished, 800000, 1)
+	shorter.DistanceMeters = 4000
+	candidates := []lineupCandidate{
+		{ID: 2, Name: "Second", Grade: 11, Rows: []db.GetAthleteHistoryRow{
+			historyRow(1, statusFinished, 1010000, 2),
+			historyRow(2, statusFinished, 1008000, 2),
+			historyRow(3, statusFinished, 1006000, 2),
+		}},
+		{ID: 4, Name: "Shorter", Grade: 9, Rows: []db.GetAthleteHistoryRow{shorter}},
+		{ID: 1, Name: "First", Grade: 12, Rows: []db.GetAthleteHistoryRow{
+			historyRow(1, statusFinished, 1000000, 1),
+			historyRow(2, statusFinished, 1002000, 1),
+			historyRow(3, statusFinished, 998000, 1),
+		}},
+		{ID: 5, Name: "Dropped", Grade: 10, Rows: []db.GetAthleteHistoryRow{
+			historyRow(1, statusDNF, 0, 0),
+		}},
+		{ID: 3, Name: "Third", Grade: 9, Rows: []db.GetAthleteHistoryRow{
+			historyRow(1, statusFinished, 1020000, 3),
+		}},
+	}
+	weights := LineupFactors{
+		SeasonBest:  defaultBestWeight,
+		RecentForm:  defaultFormWeight,
+		Consistency: defaultConsistencyWeight,
+		HeadToHead:  defaultHeadToHeadWeight,
+	}
+
+	picks, unranked := rankLineup(candidates, 5000, weights, defaultFormRaces)
+
+	var order []int32
+	for i, p := range picks {
+		order = append(order, p.AthleteID)
+		if p.Rank != i+1 {
+			t.Errorf("%s has rank %d at position %d", p.Name, p.Rank, i+1)
+		}
+	}
+	if !slices.Equal(order, []int32{1, 2, 3}) {
+		t.Fatalf("ranked %v, want [1 2 3]", order)
+	}
+	var unrankedIDs []int32
+	for _, p := range unranked {
+		unrankedIDs = append(unrankedIDs, p.AthleteID)
+	}
+	if !slices.Equal(unrankedIDs, []int32{4, 5}) {
+		t.Errorf("unranked %v, want [4 5]", unrankedIDs)
+	}
+
+	first, second, third := picks[0], picks[1], picks[2]
+	if first.Score != 100 {
+		t.Errorf("first scored %v, want 100", first.Score)
+	}
+	if first.SeasonBest != 998000 || first.RecentAverage != 1000000 || first.Races != 3 {
+		t.Errorf("first = best %s recent %s races %d", first.SeasonBest, first.RecentAverage, first.Races)
+	}
+	// First met Second in three races and Third in one.
+	if first.HeadToHeadWins != 4 || first.HeadToHeadLosses != 0 {
+		t.Errorf("first head-to-head %d-%d, want 4-0", first.HeadToHeadWins, first.HeadToHeadLosses)
+	}
+	if second.HeadToHeadWins != 1 || second.HeadToHeadLosses != 3 {
+		t.Errorf("second head-to-head %d-%d, want 1-3", second.HeadToHeadWins, second.HeadToHeadLosses)
+	}
+	if second.Score <= third.Score || second.Score >= first.Score {
+		t.Errorf("scores %v, %v, %v are not in order", first.Score, second.Score, third.Score)
+	}
+	// Third has a single race, so consistency is neutral and there is no
+	// spread to report.
+	if third.Scores.Consistency != neutralScore || third.Spread != 0 {
+		t.Errorf("third consistency %v spread %s", third.Scores.Consistency, third.Spread)
+	}
+	if third.Score != 5 {
+		t.Errorf("third scored %v, want 5", third.Score)
+	}
+	if len(first.Reasons) != 4 || first.Reasons[0] != "Season best of 16:38, 1st of 3" {
+		t.Errorf("first reasons %q", first.Reasons)
+	}
+}
+
+func TestRankLineupRecentForm(t *testing.T) {
+	// Slow start's first race drags its average over three races down, but
+	// its last two are the best recent form in the field.
+	candidates := []lineupCandidate{
+		{ID: 1, Name: "Slow start", Rows: []db.GetAthleteHistoryRow{
+			historyRow(1, statusFinished, 1100000, 0),
+			historyRow(2, statusFinished, 1000000, 0),
+			historyRow(3, statusFinished, 1000000, 0),
+		}},
+		{ID: 2, Name: "Fast start", Rows: []db.GetAthleteHistoryRow{
+			historyRow(4, statusFinished, 990000, 0),
+			historyRow(5, statusFinished, 1050000, 0),
+			historyRow(6, statusFinished, 1050000, 0),
+		}},
+	}
+	weights := LineupFactors{RecentForm: 1}
+	picks, _ := rankLineup(candidates, 5000, weights, 2)
+	if picks[0].AthleteID != 1 || picks[0].RecentAverage != 1000000 || picks[1].RecentAverage != 1050000 {
+		t.Errorf("with form over two races got %+v", picks)
+	}
+	picks, _ = rankLineup(candidates, 5000, weights, 3)
+	if picks[0].AthleteID != 2 {
+		t.Errorf("with form over three races got %s first", picks[0].Name)
+	}
+}
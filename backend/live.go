@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Live event types sent on a meet's stream. A reset tells the client that
+// events were missed and it should reload the meet's results.
+const (
+	liveResultCreated = "result.created"
+	liveResultUpdated = "result.updated"
+	liveResultDeleted = "result.deleted"
+	liveReset         = "reset"
+)
+
+const (
+	// liveHistorySize is how many recent events per meet are kept to replay
+	// to clients that reconnect.
+	liveHistorySize = 256
+	// liveBufferSize is how many events a client may fall behind by before
+	// it is disconnected.
+	liveBufferSize = 64
+	// liveHeartbeat keeps idle connections from being closed by proxies.
+	liveHeartbeat = 15 * time.Second
+	// liveRetryMs is how long browsers wait before reconnecting.
+	liveRetryMs = 3000
+)
+
+// LiveEvent is one change to a meet's results.
+type LiveEvent struct {
+	ID   int64
+	Type string
+	Data any
+}
+
+// LiveDeletedResult is the data sent when a result is deleted.
+type LiveDeletedResult struct {
+	ID int32 `json:"id"`
+}
+
+type liveSubscriber struct {
+	events chan LiveEvent
+}
+
+type liveMeet struct {
+	subscribers map[*liveSubscriber]bool
+	history     []LiveEvent
+	// evicted is the ID of the newest event dropped from history.
+	evicted int64
+}
+
+// liveBroadcaster fans result changes out to the clients watching each meet.
+// Event IDs increase across all meets and start from the time the process
+// started, so IDs handed out before a restart are recognized as stale.
+type liveBroadcaster struct {
+	mu      sync.Mutex
+	startID int64
+	lastID  int64
+	meets   map[int32]*liveMeet
+}
+
+var live = newLiveBroadcaster()
+
+func newLiveBroadcaster() *liveBroadcaster {
+	start := time.Now().UnixMilli()
+	return &liveBroadcaster{startID: start, lastID: start, meets: make(map[int32]*liveMeet)}
+}
+
+func (b *liveBroadcaster) meet(meetID int32) *liveMeet {
+	m, ok := b.meets[meetID]
+	if !ok {
+		m = &liveMeet{subscribers: make(map[*liveSubscriber]bool)}
+		b.meets[meetID] = m
+	}
+	return m
+}
+
+// publish sends an event to everyone watching a meet. A client whose buffer
+// is full is disconnected rather than holding up the others; it catches up
+// from history when it reconnects.
+func (b *liveBroadcaster) publish(meetID int32, eventType string, data any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := LiveEvent{ID: b.lastID, Type: eventType, Data: data}
+	m := b.meet(meetID)
+	m.history = append(m.history, event)
+	if len(m.history) > liveHistorySize {
+		m.evicted = m.history[0].ID
+		m.history = append([]LiveEvent(nil), m.history[1:]...)
+	}
+
+	for sub := range m.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			delete(m.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// subscribe starts watching a meet. With a lastEventID from a reconnecting
+// client it also returns the events missed since then, or a reset when they
+// are no longer all available.
+func (b *liveBroadcaster) subscribe(meetID int32, lastEventID int64) (*liveSubscriber, []LiveEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	m := b.meet(meetID)
+	sub := &liveSubscriber{events: make(chan LiveEvent, liveBufferSize)}
+	m.subscribers[sub] = true
+
+	if lastEventID == 0 {
+		return sub, nil
+	}
+	if lastEventID < b.startID || lastEventID < m.evicted || lastEventID > b.lastID {
+		return sub, []LiveEvent{{ID: b.lastID, Type: liveReset, Data: struct{}{}}}
+	}
+	var missed []LiveEvent
+	for _, e := range m.history {
+		if e.ID > lastEventID {
+			missed = append(missed, e)
+		}
+	}
+	return sub, missed
+}
+
+func (b *liveBroadcaster) unsubscribe(meetID int32, sub *liveSubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if m, ok := b.meets[meetID]; ok && m.subscribers[sub] {
+		delete(m.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// publishResult announces a created or updated result on its meet's stream.
+// The change is already saved, so a failure to load it is only logged.
+func publishResult(ctx context.Context, eventType string, resultID int32) {
+	r, err := queries.GetMeetResult(ctx, resultID)
+	if err != nil {
+		log.Printf("live: loading result %d: %v", resultID, err)
+		return
+	}
+	live.publish(r.MeetID, eventType, MeetResultResponse{
+		ID:           r.ID,
+		Status:       r.Status,
+		Time:         RaceTime(r.TimeMs.Int32),
+		Place:        r.Place.Int32,
+		AthleteID:    r.AthleteID,
+		AthleteName:  r.AthleteName,
+		AthleteGrade: gradeAt(r.AthleteGrade, r.SeasonGrade),
+		SchoolID:     r.SchoolID,
+		School:       r.SchoolName,
+		RaceID:       r.RaceID,
+		RaceName:     r.RaceName,
+		Pacing:       newPacing(RaceTime(r.TimeMs.Int32), r.DistanceMeters),
+	})
+}
+
+// resultMeet returns the meet a result belongs to, or zero if there is no
+// such result, so a change can be announced after the result is gone.
+func resultMeet(ctx context.Context, resultID int32) (int32, error) {
+	r, err := queries.GetMeetResult(ctx, resultID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return r.MeetID, nil
+}
+
+// writeLiveEvent writes one event in Server-Sent Events format.
+func writeLiveEvent(c *gin.Context, e LiveEvent) error {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
+
+func registerLiveRoutes(r *gin.Engine) {
+	// Stream a meet's result changes as Server-Sent Events. Reconnecting
+	// clients send Last-Event-ID (or ?lastEventId) to replay what they missed.
+	r.GET("/api/meets/:id/live", func(c *gin.Context) {
+		meet, ok := meetParam(c)
+		if !ok {
+			return
+		}
+
+		lastEventID := c.GetHeader("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = c.Query("lastEventId")
+		}
+		var since int64
+		if lastEventID != "" {
+			var err error
+			if since, err = strconv.ParseInt(lastEventID, 10, 64); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+				return
+			}
+		}
+
+		sub, missed := live.subscribe(meet.ID, since)
+		defer live.unsubscribe(meet.ID, sub)
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		fmt.Fprintf(c.Writer, "retry: %d\n\n", liveRetryMs)
+		c.Writer.Flush()
+
+		for _, e := range missed {
+			if err := writeLiveEvent(c, e); err != nil {
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(liveHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case e, ok := <-sub.events:
+				if !ok {
+					return
+				}
+				if err := writeLiveEvent(c, e); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(c.Writer, ": keepalive\n\n"); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			case <-c.Request.Context().Done():
+				return
+			}
+		}
+	})
+}
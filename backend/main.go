@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"os"
 	"strconv"
@@ -13,18 +15,25 @@ import (
 	"jones-county-xc/backend/db"
 
 	"github.com/gin-gonic/gin"
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
-var queries *db.Queries
+var (
+	conn    *sql.DB
+	queries *db.Queries
+)
 
 // Response types for JSON serialization
 type AthleteResponse struct {
-	ID             int32  `json:"id"`
-	Name           string `json:"name"`
-	Grade          int32  `json:"grade"`
-	PersonalRecord string `json:"personalRecord"`
-	Events         string `json:"events"`
+	ID              int32            `json:"id"`
+	Name            string           `json:"name"`
+	Grade           int32            `json:"grade"`
+	Division        string           `json:"division"`
+	SchoolID        int32            `json:"schoolId"`
+	Status          string           `json:"status"`
+	PersonalRecord  RaceTime         `json:"personalRecord"`
+	PersonalRecords []PersonalRecord `json:"personalRecords"`
+	Events          string           `json:"events"`
 }
 
 type MeetResponse struct {
@@ -32,56 +41,187 @@ type MeetResponse struct {
 	Name        string `json:"name"`
 	Date        string `json:"date"`
 	Location    string `json:"location"`
+	CourseID    int32  `json:"courseId"`
+	SeasonID    int32  `json:"seasonId"`
 	Description string `json:"description"`
 }
 
-type ResultResponse struct {
-	ID        int32  `json:"id"`
-	AthleteID int32  `json:"athleteId"`
-	MeetID    int32  `json:"meetId"`
-	Time      string `json:"time"`
-	Place     int32  `json:"place"`
+// Pacing is derived from a result's time and its race distance so every
+// result response reports pace the same way. Non-finishers have no time, so
+// their paces are blank.
+type Pacing struct {
+	DistanceMeters int32    `json:"distanceMeters"`
+	PacePerMile    RaceTime `json:"pacePerMile"`
+	PacePerKm      RaceTime `json:"pacePerKm"`
+	Equivalent5K   RaceTime `json:"equivalent5k"`
+}
+
+func newPacing(t RaceTime, distanceMeters int32) Pacing {
+	return Pacing{
+		DistanceMeters: distanceMeters,
+		PacePerMile:    t.Pace(distanceMeters, metersPerMile),
+		PacePerKm:      t.Pace(distanceMeters, metersPerKm),
+		Equivalent5K:   t.Equivalent(distanceMeters, defaultDistanceMeters),
+	}
 }
 
 type MeetResultResponse struct {
-	ID           int32  `json:"id"`
-	Time         string `json:"time"`
-	Place        int32  `json:"place"`
-	AthleteID    int32  `json:"athleteId"`
-	AthleteName  string `json:"athleteName"`
-	AthleteGrade int32  `json:"athleteGrade"`
+	ID           int32    `json:"id"`
+	Status       string   `json:"status"`
+	Time         RaceTime `json:"time"`
+	Place        int32    `json:"place"`
+	AthleteID    int32    `json:"athleteId"`
+	AthleteName  string   `json:"athleteName"`
+	AthleteGrade int32    `json:"athleteGrade"`
+	SchoolID     int32    `json:"schoolId"`
+	School       string   `json:"school"`
+	RaceID       int32    `json:"raceId"`
+	RaceName     string   `json:"raceName"`
+	Pacing
 }
 
+// CreateAthleteRequest adds or updates an athlete. New athletes without a
+// school belong to the home school; updates without one keep the athlete's
+// current school.
 type CreateAthleteRequest struct {
-	Name           string `json:"name" binding:"required"`
-	Grade          int32  `json:"grade" binding:"required"`
-	PersonalRecord string `json:"personalRecord"`
-	Events         string `json:"events"`
+	Name     string `json:"name" binding:"required"`
+	Grade    int32  `json:"grade" binding:"required"`
+	Division string `json:"division" binding:"required,oneof=boys girls"`
+	SchoolID int32  `json:"schoolId"`
+	Events   string `json:"events"`
 }
 
 type CreateMeetRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Date        string `json:"date" binding:"required"`
 	Location    string `json:"location" binding:"required"`
+	CourseID    int32  `json:"courseId"`
+	SeasonID    int32  `json:"seasonId"`
 	Description string `json:"description"`
 }
 
+// CreateResultRequest records a result. Status defaults to finished, which
+// needs a time and place; other statuses have neither.
 type CreateResultRequest struct {
-	AthleteID int32  `json:"athleteId" binding:"required"`
-	MeetID    int32  `json:"meetId" binding:"required"`
-	Time      string `json:"time" binding:"required"`
-	Place     int32  `json:"place" binding:"required"`
+	AthleteID int32    `json:"athleteId" binding:"required"`
+	MeetID    int32    `json:"meetId" binding:"required"`
+	RaceID    int32    `json:"raceId"`
+	Status    string   `json:"status" binding:"omitempty,oneof=finished dnf dns dq"`
+	Time      RaceTime `json:"time"`
+	Place     int32    `json:"place"`
 }
 
 type TopTimeResponse struct {
-	ID          int32  `json:"id"`
-	Time        string `json:"time"`
-	Place       int32  `json:"place"`
-	AthleteID   int32  `json:"athleteId"`
-	AthleteName string `json:"athleteName"`
-	MeetID      int32  `json:"meetId"`
-	MeetName    string `json:"meetName"`
-	MeetDate    string `json:"meetDate"`
+	Rank        int32    `json:"rank"`
+	ID          int32    `json:"id"`
+	Time        RaceTime `json:"time"`
+	Place       int32    `json:"place"`
+	AthleteID   int32    `json:"athleteId"`
+	AthleteName string   `json:"athleteName"`
+	MeetID      int32    `json:"meetId"`
+	MeetName    string   `json:"meetName"`
+	MeetDate    string   `json:"meetDate"`
+	RaceID      int32    `json:"raceId"`
+	RaceName    string   `json:"raceName"`
+	Pacing
+}
+
+type DivisionTopTimesResponse struct {
+	Division string            `json:"division"`
+	Times    []TopTimeResponse `json:"times"`
+}
+
+// divisions are the athlete divisions that leaderboards are split by.
+var divisions = []string{"boys", "girls"}
+
+// divisionsFromQuery returns the divisions selected by the "division" query
+// parameter, or every division when it is absent.
+func divisionsFromQuery(c *gin.Context) ([]string, error) {
+	division := c.Query("division")
+	if division == "" {
+		return divisions, nil
+	}
+	for _, d := range divisions {
+		if d == division {
+			return []string{d}, nil
+		}
+	}
+	return nil, badRequest("Invalid division, use boys or girls")
+}
+
+// divisionPattern is a LIKE pattern for the "division" query parameter that
+// matches every division when it is absent.
+func divisionPattern(c *gin.Context) (string, error) {
+	selected, err := divisionsFromQuery(c)
+	if err != nil {
+		return "", err
+	}
+	if len(selected) > 1 {
+		return "%", nil
+	}
+	return selected[0], nil
+}
+
+// requestError is an error caused by invalid client input rather than a
+// server-side failure.
+type requestError struct {
+	msg string
+}
+
+func (e *requestError) Error() string {
+	return e.msg
+}
+
+func badRequest(msg string) error {
+	return &requestError{msg: msg}
+}
+
+// errorStatus returns the HTTP status an error should be reported with.
+func errorStatus(err error) int {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// isDuplicateKey reports whether a write failed on a unique key.
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// defaultDistanceMeters is the standard cross-country race distance used when
+// a single headline mark is needed.
+const defaultDistanceMeters = 5000
+
+func newAthleteResponse(a db.Athlete, records []PersonalRecord) AthleteResponse {
+	if records == nil {
+		records = []PersonalRecord{}
+	}
+	return AthleteResponse{
+		ID:              a.ID,
+		Name:            a.Name,
+		Grade:           a.Grade,
+		Division:        a.Division,
+		SchoolID:        a.SchoolID,
+		Status:          a.Status,
+		PersonalRecord:  recordFor(records, defaultDistanceMeters),
+		PersonalRecords: records,
+		Events:          a.Events.String,
+	}
+}
+
+func newMeetResponse(m db.Meet) MeetResponse {
+	return MeetResponse{
+		ID:          m.ID,
+		Name:        m.Name,
+		Date:        m.Date.Format("2006-01-02"),
+		Location:    m.Location,
+		CourseID:    m.CourseID.Int32,
+		SeasonID:    m.SeasonID.Int32,
+		Description: m.Description.String,
+	}
 }
 
 func getEnv(key, fallback string) string {
@@ -98,7 +238,8 @@ func main() {
 	dbName := getEnv("DB_NAME", "jones_county_xc")
 
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:3306)/%s?parseTime=true", dbUser, dbPassword, dbHost, dbName)
-	conn, err := sql.Open("mysql", dsn)
+	var err error
+	conn, err = sql.Open("mysql", dsn)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
@@ -111,7 +252,36 @@ func main() {
 
 	queries = db.New(conn)
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(os.Args[2:])
+		return
+	}
+	if err = migrateOnStartup(); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import-results":
+			runImportCommand(os.Args[2:])
+		case "create-admin":
+			runCreateAdminCommand(os.Args[2:])
+		case "seed":
+			seeded, err := seedDatabase(context.Background())
+			if err != nil {
+				log.Fatal(err)
+			}
+			if !seeded {
+				log.Println("Database already has data; nothing loaded")
+			}
+		default:
+			log.Fatalf("Unknown command %q", os.Args[1])
+		}
+		return
+	}
+
 	r := gin.Default()
+	r.Use(authenticate(), protectWrites())
 
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -126,9 +296,75 @@ func main() {
 		})
 	})
 
-	// Get all athletes
+	// List a school's athletes a page at a time (the home school unless
+	// ?schoolId is given), filtered by ?grade, ?division, ?status and ?q
+	// (name) and sorted by name or grade; alumni are included unless a status
+	// is given
 	r.GET("/api/athletes", func(c *gin.Context) {
-		athletes, err := queries.GetAllAthletes(context.Background())
+		list, err := parseListQuery(c, []string{"name", "grade"}, "name")
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		minGrade, maxGrade := int32(0), int32(math.MaxInt32)
+		if g := c.Query("grade"); g != "" {
+			grade, err := strconv.Atoi(g)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid grade"})
+				return
+			}
+			minGrade, maxGrade = int32(grade), int32(grade)
+		}
+
+		division, err := divisionPattern(c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		status := c.Query("status")
+		if status != "" && status != athleteActive && status != athleteAlumni {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, use active or alumni"})
+			return
+		}
+
+		schoolID, err := schoolQuery(context.Background(), c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		filter := db.CountAthletesParams{
+			SchoolID: schoolID,
+			MinGrade: minGrade,
+			MaxGrade: maxGrade,
+			Division: division,
+			Status:   equalsPattern(status),
+			Name:     containsPattern(c.Query("q")),
+		}
+		total, err := queries.CountAthletes(context.Background(), filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		athletes, err := queries.ListAthletes(context.Background(), db.ListAthletesParams{
+			SchoolID: filter.SchoolID,
+			MinGrade: filter.MinGrade,
+			MaxGrade: filter.MaxGrade,
+			Division: filter.Division,
+			Status:   filter.Status,
+			Name:     filter.Name,
+			Sort:     list.Sort,
+			Limit:    list.Limit,
+			Offset:   list.Offset(),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		records, err := athletesPersonalRecords(context.Background(), athletes)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -136,15 +372,9 @@ func main() {
 
 		response := make([]AthleteResponse, len(athletes))
 		for i, a := range athletes {
-			response[i] = AthleteResponse{
-				ID:             a.ID,
-				Name:           a.Name,
-				Grade:          a.Grade,
-				PersonalRecord: a.PersonalRecord.String,
-				Events:         a.Events.String,
-			}
+			response[i] = newAthleteResponse(a, records[a.ID])
 		}
-		c.JSON(http.StatusOK, response)
+		c.JSON(http.StatusOK, newPageResponse(c, response, total, list))
 	})
 
 	// Get athlete by ID
@@ -165,18 +395,54 @@ func main() {
 			return
 		}
 
-		c.JSON(http.StatusOK, AthleteResponse{
-			ID:             athlete.ID,
-			Name:           athlete.Name,
-			Grade:          athlete.Grade,
-			PersonalRecord: athlete.PersonalRecord.String,
-			Events:         athlete.Events.String,
-		})
+		marks, err := queries.GetAthleteResultMarks(context.Background(), athlete.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, newAthleteResponse(athlete, personalRecords(marksFromAthleteRows(marks))))
 	})
 
-	// Get all meets
+	// List meets a page at a time, filtered by ?from and ?to dates, ?location
+	// and ?q (name) and sorted by date or name
 	r.GET("/api/meets", func(c *gin.Context) {
-		meets, err := queries.GetAllMeets(context.Background())
+		list, err := parseListQuery(c, []string{"date", "name"}, "date")
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		from, err := dateQuery(c, "from", earliestDate)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		to, err := dateQuery(c, "to", latestDate)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		filter := db.CountMeetsParams{
+			FromDate: from,
+			ToDate:   to,
+			Location: containsPattern(c.Query("location")),
+			Name:     containsPattern(c.Query("q")),
+		}
+		total, err := queries.CountMeets(context.Background(), filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		meets, err := queries.ListMeets(context.Background(), db.ListMeetsParams{
+			FromDate: filter.FromDate,
+			ToDate:   filter.ToDate,
+			Location: filter.Location,
+			Name:     filter.Name,
+			Sort:     list.Sort,
+			Limit:    list.Limit,
+			Offset:   list.Offset(),
+		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -184,18 +450,14 @@ func main() {
 
 		response := make([]MeetResponse, len(meets))
 		for i, m := range meets {
-			response[i] = MeetResponse{
-				ID:          m.ID,
-				Name:        m.Name,
-				Date:        m.Date.Format("2006-01-02"),
-				Location:    m.Location,
-				Description: m.Description.String,
-			}
+			response[i] = newMeetResponse(m)
 		}
-		c.JSON(http.StatusOK, response)
+		c.JSON(http.StatusOK, newPageResponse(c, response, total, list))
 	})
 
-	// Get results for a specific meet (with athlete names)
+	// List a meet's results a page at a time, race by race, filtered by
+	// ?raceId, ?division and ?q (athlete name) and sorted within each race by
+	// place, time or name
 	r.GET("/api/meets/:id/results", func(c *gin.Context) {
 		id, err := strconv.Atoi(c.Param("id"))
 		if err != nil {
@@ -203,7 +465,50 @@ func main() {
 			return
 		}
 
-		results, err := queries.GetMeetResults(context.Background(), int32(id))
+		list, err := parseListQuery(c, []string{"place", "time", "name"}, "place")
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		minRace, maxRace := int32(0), int32(math.MaxInt32)
+		if rid := c.Query("raceId"); rid != "" {
+			raceID, err := strconv.Atoi(rid)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid race ID"})
+				return
+			}
+			minRace, maxRace = int32(raceID), int32(raceID)
+		}
+
+		division, err := divisionPattern(c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		filter := db.CountMeetResultsParams{
+			MeetID:    int32(id),
+			MinRaceID: minRace,
+			MaxRaceID: maxRace,
+			Division:  division,
+			Name:      containsPattern(c.Query("q")),
+		}
+		total, err := queries.CountMeetResults(context.Background(), filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		results, err := queries.ListMeetResults(context.Background(), db.ListMeetResultsParams{
+			MeetID:    filter.MeetID,
+			MinRaceID: filter.MinRaceID,
+			MaxRaceID: filter.MaxRaceID,
+			Division:  filter.Division,
+			Name:      filter.Name,
+			Sort:      list.Sort,
+			Limit:     list.Limit,
+			Offset:    list.Offset(),
+		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -213,64 +518,81 @@ func main() {
 		for i, r := range results {
 			response[i] = MeetResultResponse{
 				ID:           r.ID,
-				Time:         r.Time,
-				Place:        r.Place,
+				Status:       r.Status,
+				Time:         RaceTime(r.TimeMs.Int32),
+				Place:        r.Place.Int32,
 				AthleteID:    r.AthleteID,
 				AthleteName:  r.AthleteName,
-				AthleteGrade: r.AthleteGrade,
+				AthleteGrade: gradeAt(r.AthleteGrade, r.SeasonGrade),
+				SchoolID:     r.SchoolID,
+				School:       r.SchoolName,
+				RaceID:       r.RaceID,
+				RaceName:     r.RaceName,
+				Pacing:       newPacing(RaceTime(r.TimeMs.Int32), r.DistanceMeters),
 			}
 		}
-		c.JSON(http.StatusOK, response)
+		c.JSON(http.StatusOK, newPageResponse(c, response, total, list))
 	})
 
-	// Create a new result
-	r.POST("/api/results", func(c *gin.Context) {
-		var req CreateResultRequest
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	// Get team scores for a meet
+	r.GET("/api/meets/:id/team-scores", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meet ID"})
 			return
 		}
 
-		result, err := queries.CreateResult(context.Background(), db.CreateResultParams{
-			AthleteID: req.AthleteID,
-			MeetID:    req.MeetID,
-			Time:      req.Time,
-			Place:     req.Place,
-		})
+		results, err := queries.GetMeetResults(context.Background(), int32(id))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		id, _ := result.LastInsertId()
-		c.JSON(http.StatusCreated, gin.H{
-			"id":      id,
-			"message": "Result created successfully",
-		})
+		// Each race in the meet is scored on its own.
+		response := []RaceTeamScoresResponse{}
+		var finishers []Finisher
+		for i, r := range results {
+			if r.Status == statusFinished {
+				finishers = append(finishers, Finisher{
+					ResultID:  r.ID,
+					AthleteID: r.AthleteID,
+					Name:      r.AthleteName,
+					Team:      r.SchoolName,
+					Place:     r.Place.Int32,
+					Time:      RaceTime(r.TimeMs.Int32),
+				})
+			}
+			if i == len(results)-1 || results[i+1].RaceID != r.RaceID {
+				response = append(response, RaceTeamScoresResponse{
+					RaceID:   r.RaceID,
+					RaceName: r.RaceName,
+					Teams:    ScoreTeams(finishers),
+				})
+				finishers = nil
+			}
+		}
+		c.JSON(http.StatusOK, response)
 	})
 
-	// Get top 10 fastest times
-	r.GET("/api/top-times", func(c *gin.Context) {
-		times, err := queries.GetTopTimes(context.Background())
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Create a new result
+	r.POST("/api/results", func(c *gin.Context) {
+		var req CreateResultRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		response := make([]TopTimeResponse, len(times))
-		for i, t := range times {
-			response[i] = TopTimeResponse{
-				ID:          t.ID,
-				Time:        t.Time,
-				Place:       t.Place,
-				AthleteID:   t.AthleteID,
-				AthleteName: t.AthleteName,
-				MeetID:      t.MeetID,
-				MeetName:    t.MeetName,
-				MeetDate:    t.MeetDate.Format("2006-01-02"),
-			}
+		created, err := createResult(context.Background(), queries, req)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
 		}
-		c.JSON(http.StatusOK, response)
+		publishResult(context.Background(), liveResultCreated, int32(created.ID))
+
+		c.JSON(http.StatusCreated, CreateResultResponse{
+			Message:       "Result created successfully",
+			CreatedResult: created,
+		})
 	})
 
 	// Create a new athlete
@@ -281,11 +603,18 @@ func main() {
 			return
 		}
 
+		schoolID, err := athleteSchool(context.Background(), req.SchoolID)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
 		result, err := queries.CreateAthlete(context.Background(), db.CreateAthleteParams{
-			Name:           req.Name,
-			Grade:          req.Grade,
-			PersonalRecord: sql.NullString{String: req.PersonalRecord, Valid: req.PersonalRecord != ""},
-			Events:         sql.NullString{String: req.Events, Valid: req.Events != ""},
+			Name:     req.Name,
+			Grade:    req.Grade,
+			Division: req.Division,
+			SchoolID: schoolID,
+			Events:   sql.NullString{String: req.Events, Valid: req.Events != ""},
 		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -310,12 +639,32 @@ func main() {
 			return
 		}
 
+		athlete, err := queries.GetAthleteByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Athlete not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Leaving out the school keeps the athlete at their current one.
+		schoolID := athlete.SchoolID
+		if req.SchoolID != 0 {
+			if schoolID, err = athleteSchool(context.Background(), req.SchoolID); err != nil {
+				c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+		}
+
 		err = queries.UpdateAthlete(context.Background(), db.UpdateAthleteParams{
-			ID:             int32(id),
-			Name:           req.Name,
-			Grade:          req.Grade,
-			PersonalRecord: sql.NullString{String: req.PersonalRecord, Valid: req.PersonalRecord != ""},
-			Events:         sql.NullString{String: req.Events, Valid: req.Events != ""},
+			ID:       int32(id),
+			Name:     req.Name,
+			Grade:    req.Grade,
+			Division: req.Division,
+			SchoolID: schoolID,
+			Events:   sql.NullString{String: req.Events, Valid: req.Events != ""},
 		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -340,7 +689,7 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{"message": "Athlete deleted successfully"})
 	})
 
-	// Create a new meet
+	// Create a new meet, with an open race over its course distance
 	r.POST("/api/meets", func(c *gin.Context) {
 		var req CreateMeetRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -354,18 +703,48 @@ func main() {
 			return
 		}
 
-		result, err := queries.CreateMeet(context.Background(), db.CreateMeetParams{
+		courseID, err := courseForMeet(context.Background(), req.CourseID)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		seasonID, err := seasonForMeet(context.Background(), req.SeasonID, date)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		tx, err := conn.BeginTx(context.Background(), nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback()
+		qtx := queries.WithTx(tx)
+
+		result, err := qtx.CreateMeet(context.Background(), db.CreateMeetParams{
 			Name:        req.Name,
 			Date:        date,
 			Location:    req.Location,
+			CourseID:    courseID,
+			SeasonID:    seasonID,
 			Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
 		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-
 		id, _ := result.LastInsertId()
+		if err := createDefaultRace(context.Background(), qtx, int32(id), courseID); err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
 		c.JSON(http.StatusCreated, gin.H{"id": id, "message": "Meet created successfully"})
 	})
 
@@ -389,11 +768,25 @@ func main() {
 			return
 		}
 
+		courseID, err := courseForMeet(context.Background(), req.CourseID)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		seasonID, err := seasonForMeet(context.Background(), req.SeasonID, date)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
 		err = queries.UpdateMeet(context.Background(), db.UpdateMeetParams{
 			ID:          int32(id),
 			Name:        req.Name,
 			Date:        date,
 			Location:    req.Location,
+			CourseID:    courseID,
+			SeasonID:    seasonID,
 			Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
 		})
 		if err != nil {
@@ -432,18 +825,61 @@ func main() {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		if err := validateResultStatus(&req); err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if _, err := queries.GetAthleteByID(context.Background(), req.AthleteID); err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Athlete not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		race, err := resolveRace(context.Background(), queries, req.MeetID, req.RaceID)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		previousMeet, err := resultMeet(context.Background(), int32(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 
 		err = queries.UpdateResult(context.Background(), db.UpdateResultParams{
 			ID:        int32(id),
 			AthleteID: req.AthleteID,
 			MeetID:    req.MeetID,
-			Time:      req.Time,
-			Place:     req.Place,
+			RaceID:    race.ID,
+			Status:    req.Status,
+			TimeMs:    nullResultValue(req.Time.Milliseconds()),
+			Place:     nullResultValue(req.Place),
 		})
 		if err != nil {
+			if isDuplicateKey(err) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Athlete %d already has a result in %s", req.AthleteID, race.Name)})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		if err := dropStaleSplits(context.Background(), queries, int32(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// A result moved to another meet leaves one stream and joins the
+		// other.
+		if previousMeet == req.MeetID {
+			publishResult(context.Background(), liveResultUpdated, int32(id))
+		} else if previousMeet != 0 {
+			live.publish(previousMeet, liveResultDeleted, LiveDeletedResult{ID: int32(id)})
+			publishResult(context.Background(), liveResultCreated, int32(id))
+		}
 		c.JSON(http.StatusOK, gin.H{"message": "Result updated successfully"})
 	})
 
@@ -455,13 +891,40 @@ func main() {
 			return
 		}
 
+		meetID, err := resultMeet(context.Background(), int32(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
 		err = queries.DeleteResult(context.Background(), int32(id))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		if meetID != 0 {
+			live.publish(meetID, liveResultDeleted, LiveDeletedResult{ID: int32(id)})
+		}
 		c.JSON(http.StatusOK, gin.H{"message": "Result deleted successfully"})
 	})
 
+	registerLeaderboardRoutes(r)
+	registerProfileRoutes(r)
+	registerCompareRoutes(r)
+	registerLineupRoutes(r)
+	registerPredictionRoutes(r)
+	registerRaceRoutes(r)
+	registerEntryRoutes(r)
+	registerTimingRoutes(r)
+	registerCourseRoutes(r)
+	registerSeasonRoutes(r)
+	registerSchoolRoutes(r)
+	registerResultRoutes(r)
+	registerLiveRoutes(r)
+	registerSplitRoutes(r)
+	registerWorkoutRoutes(r)
+	registerImportRoutes(r)
+	registerAuthRoutes(r)
+
 	r.Run(":8080")
 }
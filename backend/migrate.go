@@ -0,0 +1,636 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+//go:embed seed.sql
+var seedData string
+
+// migrationName matches files such as 0002_add_splits.up.sql.
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// createTable picks the table name out of a CREATE TABLE statement.
+var createTable = regexp.MustCompile(`(?i)^CREATE TABLE (\w+)`)
+
+// legacyRaces upgrades a database built before races existed. Every meet
+// gets a default race and its existing results are moved into it; split them
+// into real divisions afterwards through the races API.
+const legacyRaces = `
+CREATE TABLE races (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    meet_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    distance_meters INT NOT NULL,
+    start_time DATETIME,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (meet_id) REFERENCES meets(id) ON DELETE CASCADE
+);
+
+INSERT INTO races (meet_id, name, distance_meters)
+SELECT id, 'Open 5K', 5000
+FROM meets;
+
+ALTER TABLE results ADD COLUMN race_id INT AFTER meet_id;
+
+UPDATE results r
+JOIN races ra ON ra.meet_id = r.meet_id
+SET r.race_id = ra.id;
+
+ALTER TABLE results
+    MODIFY race_id INT NOT NULL,
+    ADD FOREIGN KEY (race_id) REFERENCES races(id) ON DELETE CASCADE;
+`
+
+// legacyColumns are the column changes a database built from an older
+// schema.sql needs to match the first migration, in order. Each runs only
+// while its column is still missing, or still present when drop is set, so an
+// adoption that stopped part way picks up where it left off. Results times
+// are converted separately by convertLegacyTimes.
+var legacyColumns = []struct {
+	table, column string
+	drop          bool
+	script        string
+}{
+	{"athletes", "personal_record", true, "ALTER TABLE athletes DROP COLUMN personal_record"},
+	{"athletes", "personal_record_ms", true, "ALTER TABLE athletes DROP COLUMN personal_record_ms"},
+	// Athletes from before divisions are left without one; set it through
+	// PUT /api/athletes/:id.
+	{"athletes", "division", false, `
+ALTER TABLE athletes ADD COLUMN division VARCHAR(10) NOT NULL DEFAULT '' AFTER grade;
+ALTER TABLE athletes ALTER division DROP DEFAULT;
+`},
+	{"athletes", "status", false, "ALTER TABLE athletes ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'active' AFTER division"},
+	{"meets", "course_id", false, `
+ALTER TABLE meets
+    ADD COLUMN course_id INT AFTER location,
+    ADD FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE SET NULL;
+`},
+	{"meets", "season_id", false, `
+ALTER TABLE meets
+    ADD COLUMN season_id INT AFTER course_id,
+    ADD FOREIGN KEY (season_id) REFERENCES seasons(id) ON DELETE SET NULL;
+`},
+}
+
+type migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type appliedMigration struct {
+	Name     string
+	Checksum string
+}
+
+// loadMigrations reads the embedded migrations in version order. Every
+// version needs both an up and a down file.
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, e := range entries {
+		m := migrationName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", e.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+		body, err := migrationFiles.ReadFile(path.Join("migrations", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %04d has files with different names", version)
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+			sum := sha256.Sum256(body)
+			mig.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both up and down files", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// splitStatements breaks a migration into single statements, since the MySQL
+// driver runs one at a time. Comments are dropped and semicolons inside
+// quotes are left alone, including quotes escaped with a backslash or by
+// doubling them.
+func splitStatements(script string) []string {
+	var statements []string
+	var cur strings.Builder
+	var quote byte
+	for i := 0; i < len(script); i++ {
+		ch := script[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' && quote != '`' && i+1 < len(script) {
+				cur.WriteByte(ch)
+				i++
+				ch = script[i]
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '-' && strings.HasPrefix(script[i:], "--"):
+			if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+				i += end
+				ch = '\n'
+			} else {
+				i = len(script)
+				continue
+			}
+		case ch == ';':
+			if s := strings.TrimSpace(cur.String()); s != "" {
+				statements = append(statements, s)
+			}
+			cur.Reset()
+			continue
+		}
+		cur.WriteByte(ch)
+	}
+	if s := strings.TrimSpace(cur.String()); s != "" {
+		statements = append(statements, s)
+	}
+	return statements
+}
+
+func ensureMigrationsTable(ctx context.Context) error {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+)`)
+	return err
+}
+
+// appliedMigrations loads the tracking table and checks it against the
+// embedded migrations: an applied migration must still exist unchanged.
+func appliedMigrations(ctx context.Context, migrations []migration) (map[int]appliedMigration, error) {
+	if err := ensureMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, checksum FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.Name, &a.Checksum); err != nil {
+			return nil, err
+		}
+		applied[version] = a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	known := make(map[int]migration, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = m
+	}
+	for version, a := range applied {
+		m, ok := known[version]
+		if !ok {
+			return nil, fmt.Errorf("database has migration %04d_%s, which this build does not know about", version, a.Name)
+		}
+		if m.Checksum != a.Checksum {
+			return nil, fmt.Errorf("migration %04d_%s has been modified since it was applied", version, m.Name)
+		}
+	}
+	return applied, nil
+}
+
+func tableExists(ctx context.Context, name string) (bool, error) {
+	var count int
+	err := conn.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?",
+		name).Scan(&count)
+	return count > 0, err
+}
+
+func columnExists(ctx context.Context, table, column string) (bool, error) {
+	var count int
+	err := conn.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?",
+		table, column).Scan(&count)
+	return count > 0, err
+}
+
+// adoptLegacySchema brings a database built from an older schema.sql, which
+// has tables but no migration history, up to the first migration and records
+// it as applied. Missing tables are created, a missing races table through
+// legacyRaces so existing results are kept; the legacyColumns are added or
+// dropped, VARCHAR result times are converted to milliseconds and results get
+// their one-result-per-race key. It reports whether the database was adopted.
+func adoptLegacySchema(ctx context.Context, first migration, applied map[int]appliedMigration) (bool, error) {
+	if len(applied) > 0 {
+		return false, nil
+	}
+	legacy, err := tableExists(ctx, "athletes")
+	if err != nil || !legacy {
+		return false, err
+	}
+
+	for _, stmt := range splitStatements(first.Up) {
+		m := createTable.FindStringSubmatch(stmt)
+		if m == nil {
+			return false, fmt.Errorf("migration %04d_%s: cannot adopt a legacy schema with statement %q", first.Version, first.Name, stmt)
+		}
+		exists, err := tableExists(ctx, m[1])
+		if err != nil {
+			return false, err
+		}
+		if exists {
+			continue
+		}
+		script := stmt
+		if m[1] == "races" {
+			script = legacyRaces
+		}
+		if err := runStatements(ctx, first, script); err != nil {
+			return false, err
+		}
+		log.Printf("Created table %s in legacy schema", m[1])
+	}
+
+	for _, c := range legacyColumns {
+		exists, err := columnExists(ctx, c.table, c.column)
+		if err != nil {
+			return false, err
+		}
+		if exists != c.drop {
+			continue
+		}
+		if err := runStatements(ctx, first, c.script); err != nil {
+			return false, err
+		}
+		if c.drop {
+			log.Printf("Dropped column %s.%s from legacy schema", c.table, c.column)
+		} else {
+			log.Printf("Added column %s.%s to legacy schema", c.table, c.column)
+		}
+	}
+	if err := convertLegacyTimes(ctx, first); err != nil {
+		return false, err
+	}
+	if err := addLegacyResultKey(ctx, first); err != nil {
+		return false, err
+	}
+
+	var undivided int
+	if err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM athletes WHERE division = ''").Scan(&undivided); err != nil {
+		return false, err
+	}
+	if undivided > 0 {
+		log.Printf("%d athletes have no division; set one through PUT /api/athletes/:id", undivided)
+	}
+
+	_, err = conn.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)",
+		first.Version, first.Name, first.Checksum)
+	if err != nil {
+		return false, err
+	}
+	applied[first.Version] = appliedMigration{Name: first.Name, Checksum: first.Checksum}
+	log.Printf("Adopted legacy schema as migration %04d_%s", first.Version, first.Name)
+	return true, nil
+}
+
+// convertLegacyTimes replaces the VARCHAR results.time of the original schema
+// with time_ms. Every time is parsed before anything changes, so one that
+// cannot be read stops the adoption with the results as they were.
+func convertLegacyTimes(ctx context.Context, first migration) error {
+	legacy, err := columnExists(ctx, "results", "time")
+	if err != nil || !legacy {
+		return err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT id, time FROM results")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	times := make(map[int32]RaceTime)
+	var bad []string
+	for rows.Next() {
+		var id int32
+		var s string
+		if err := rows.Scan(&id, &s); err != nil {
+			return err
+		}
+		t, err := ParseRaceTime(s)
+		if err != nil {
+			bad = append(bad, fmt.Sprintf("result %d (%q)", id, s))
+			continue
+		}
+		times[id] = t
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(bad) > 0 {
+		return fmt.Errorf("migration %04d_%s: cannot read the times of %s; correct them and run migrate up again",
+			first.Version, first.Name, strings.Join(bad, ", "))
+	}
+
+	converted, err := columnExists(ctx, "results", "time_ms")
+	if err != nil {
+		return err
+	}
+	if !converted {
+		if err := runStatements(ctx, first, "ALTER TABLE results ADD COLUMN time_ms INT AFTER race_id"); err != nil {
+			return err
+		}
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for id, t := range times {
+		if _, err := tx.ExecContext(ctx, "UPDATE results SET time_ms = ? WHERE id = ?", t.Milliseconds(), id); err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", first.Version, first.Name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if err := runStatements(ctx, first, "ALTER TABLE results MODIFY time_ms INT NOT NULL, DROP COLUMN time"); err != nil {
+		return err
+	}
+	log.Printf("Converted %d result times to milliseconds", len(times))
+	return nil
+}
+
+// addLegacyResultKey gives results the unique (race_id, athlete_id) key of
+// the first migration. An athlete with two results in one race stops the
+// adoption until one of them is deleted.
+func addLegacyResultKey(ctx context.Context, first migration) error {
+	var keys int
+	err := conn.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM information_schema.statistics
+WHERE table_schema = DATABASE() AND table_name = 'results'
+  AND non_unique = 0 AND column_name = 'race_id' AND seq_in_index = 1`).Scan(&keys)
+	if err != nil || keys > 0 {
+		return err
+	}
+
+	var raceID, athleteID int32
+	err = conn.QueryRowContext(ctx,
+		"SELECT race_id, athlete_id FROM results GROUP BY race_id, athlete_id HAVING COUNT(*) > 1 LIMIT 1").
+		Scan(&raceID, &athleteID)
+	if err == nil {
+		return fmt.Errorf("migration %04d_%s: athlete %d has more than one result in race %d; delete the extras and run migrate up again",
+			first.Version, first.Name, athleteID, raceID)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return runStatements(ctx, first, "ALTER TABLE results ADD UNIQUE KEY (race_id, athlete_id)")
+}
+
+// runStatements executes a migration script. MySQL commits DDL implicitly,
+// so a failure part way through leaves the earlier statements applied.
+func runStatements(ctx context.Context, m migration, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	return nil
+}
+
+// migrateUp applies every pending migration in order and returns how many
+// ran.
+func migrateUp(ctx context.Context) (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	applied, err := appliedMigrations(ctx, migrations)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	if len(migrations) > 0 {
+		adopted, err := adoptLegacySchema(ctx, migrations[0], applied)
+		if err != nil {
+			return 0, err
+		}
+		if adopted {
+			count++
+		}
+	}
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := runStatements(ctx, m, m.Up); err != nil {
+			return count, err
+		}
+		_, err := conn.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)",
+			m.Version, m.Name, m.Checksum)
+		if err != nil {
+			return count, err
+		}
+		log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+		count++
+	}
+	return count, nil
+}
+
+// migrateDown rolls back the most recently applied migrations, newest first.
+func migrateDown(ctx context.Context, steps int) (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	applied, err := appliedMigrations(ctx, migrations)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if err := runStatements(ctx, m, m.Down); err != nil {
+			return count, err
+		}
+		if _, err := conn.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", m.Version); err != nil {
+			return count, err
+		}
+		log.Printf("Rolled back migration %04d_%s", m.Version, m.Name)
+		count++
+	}
+	return count, nil
+}
+
+// runMigrateCommand implements the migrate subcommand:
+//
+//	server migrate up
+//	server migrate down [steps]
+//	server migrate status
+func runMigrateCommand(args []string) {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "usage: server migrate up | down [steps] | status")
+		os.Exit(2)
+	}
+	if len(args) == 0 {
+		usage()
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		count, err := migrateUp(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Applied %d migrations\n", count)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				usage()
+			}
+			steps = n
+		}
+		count, err := migrateDown(ctx, steps)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Rolled back %d migrations\n", count)
+	case "status":
+		migrations, err := loadMigrations()
+		if err != nil {
+			log.Fatal(err)
+		}
+		applied, err := appliedMigrations(ctx, migrations)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, m := range migrations {
+			state := "pending"
+			if _, ok := applied[m.Version]; ok {
+				state = "applied"
+			}
+			fmt.Printf("%04d_%-30s %s\n", m.Version, m.Name, state)
+		}
+	default:
+		usage()
+	}
+}
+
+// seedDatabase loads the sample data from seed.sql into a database that has
+// no athletes yet. It reports whether anything was loaded.
+func seedDatabase(ctx context.Context) (bool, error) {
+	var count int
+	if err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM athletes").Scan(&count); err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	for _, stmt := range splitStatements(seedData) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return false, fmt.Errorf("seed data: %w", err)
+		}
+	}
+	return true, tx.Commit()
+}
+
+// migrateOnStartup brings the schema up to date before serving, unless
+// AUTO_MIGRATE is false, in which case it only refuses to start on a database
+// whose applied migrations do not match this build. With SEED_DATA=true an
+// empty database also gets the sample data.
+func migrateOnStartup() error {
+	ctx := context.Background()
+	if getEnv("AUTO_MIGRATE", "true") == "false" {
+		migrations, err := loadMigrations()
+		if err != nil {
+			return err
+		}
+		applied, err := appliedMigrations(ctx, migrations)
+		if err != nil {
+			return err
+		}
+		if pending := len(migrations) - len(applied); pending > 0 {
+			log.Printf("%d migrations pending; run \"server migrate up\"", pending)
+		}
+		return nil
+	}
+
+	if _, err := migrateUp(ctx); err != nil {
+		return err
+	}
+	if getEnv("SEED_DATA", "false") == "true" {
+		seeded, err := seedDatabase(ctx)
+		if err != nil {
+			return err
+		}
+		if seeded {
+			log.Println("Loaded sample data")
+		}
+	}
+	return nil
+}
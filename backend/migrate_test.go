@@ -0,0 +1,46 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"single", "SELECT 1", []string{"SELECT 1"}},
+		{"several", "SELECT 1;\nSELECT 2;\n", []string{"SELECT 1", "SELECT 2"}},
+		{"comments", "-- first\nSELECT 1; -- trailing\n-- last", []string{"SELECT 1"}},
+		{"semicolon in quotes", "SELECT 'a;b'; SELECT \"c;d\"", []string{"SELECT 'a;b'", "SELECT \"c;d\""}},
+		{"semicolon in backticks", "SELECT `a;b` FROM t", []string{"SELECT `a;b` FROM t"}},
+		{"doubled quote", "SELECT 'it''s; fine'; SELECT 2", []string{"SELECT 'it''s; fine'", "SELECT 2"}},
+		{"backslash quote", `SELECT 'it\'s; fine'; SELECT 2`, []string{`SELECT 'it\'s; fine'`, "SELECT 2"}},
+		{"escaped backslash", `SELECT 'a\\'; SELECT 2`, []string{`SELECT 'a\\'`, "SELECT 2"}},
+		{"dashes in quotes", "SELECT '--x;'", []string{"SELECT '--x;'"}},
+		{"empty", " ;\n; ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrationsSplitIntoCreateTables(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// adoptLegacySchema can only adopt a first migration made of CREATE TABLE
+	// statements.
+	for _, stmt := range splitStatements(migrations[0].Up) {
+		if createTable.FindStringSubmatch(stmt) == nil {
+			t.Errorf("first migration has a statement that is not CREATE TABLE: %q", stmt)
+		}
+	}
+}
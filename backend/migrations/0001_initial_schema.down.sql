@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS results;
+DROP TABLE IF EXISTS races;
+DROP TABLE IF EXISTS meets;
+DROP TABLE IF EXISTS courses;
+DROP TABLE IF EXISTS season_rosters;
+DROP TABLE IF EXISTS seasons;
+DROP TABLE IF EXISTS athletes;
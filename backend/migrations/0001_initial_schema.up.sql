@@ -0,0 +1,97 @@
+-- The schema as it stood before versioned migrations. Databases built from
+-- an older schema.sql are not run through this file; migrateUp adopts them by
+-- bringing their tables and columns up to it and recording this migration.
+
+CREATE TABLE athletes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    grade INT NOT NULL,
+    division VARCHAR(10) NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'active',
+    events VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE seasons (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    year INT NOT NULL UNIQUE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE season_rosters (
+    season_id INT NOT NULL,
+    athlete_id INT NOT NULL,
+    grade INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (season_id, athlete_id),
+    FOREIGN KEY (season_id) REFERENCES seasons(id) ON DELETE CASCADE,
+    FOREIGN KEY (athlete_id) REFERENCES athletes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE courses (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    venue VARCHAR(255) NOT NULL,
+    distance_meters INT NOT NULL,
+    surface VARCHAR(50),
+    latitude DOUBLE,
+    longitude DOUBLE,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE meets (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    date DATE NOT NULL,
+    location VARCHAR(255) NOT NULL,
+    course_id INT,
+    season_id INT,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE SET NULL,
+    FOREIGN KEY (season_id) REFERENCES seasons(id) ON DELETE SET NULL
+);
+
+CREATE TABLE races (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    meet_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    distance_meters INT NOT NULL,
+    start_time DATETIME,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (meet_id) REFERENCES meets(id) ON DELETE CASCADE
+);
+
+CREATE TABLE results (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    athlete_id INT NOT NULL,
+    meet_id INT NOT NULL,
+    race_id INT NOT NULL,
+    time_ms INT NOT NULL,
+    place INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (athlete_id) REFERENCES athletes(id) ON DELETE CASCADE,
+    FOREIGN KEY (meet_id) REFERENCES meets(id) ON DELETE CASCADE,
+    FOREIGN KEY (race_id) REFERENCES races(id) ON DELETE CASCADE,
+    UNIQUE KEY (race_id, athlete_id)
+);
+
+CREATE TABLE users (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE sessions (
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id INT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+DELETE FROM results WHERE status <> 'finished';
+
+ALTER TABLE results
+    DROP COLUMN status,
+    MODIFY time_ms INT NOT NULL,
+    MODIFY place INT NOT NULL;
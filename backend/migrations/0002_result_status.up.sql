@@ -0,0 +1,6 @@
+-- Results can record athletes who did not finish (dnf), did not start (dns)
+-- or were disqualified (dq). Only finished results have a time and place.
+ALTER TABLE results
+    ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'finished' AFTER race_id,
+    MODIFY time_ms INT,
+    MODIFY place INT;
@@ -0,0 +1,11 @@
+-- Checkpoint times within a result, e.g. the 1-mile and 2-mile marks. The
+-- last split of a result is always at the finish.
+CREATE TABLE splits (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    result_id INT NOT NULL,
+    distance_meters INT NOT NULL,
+    elapsed_ms INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (result_id) REFERENCES results(id) ON DELETE CASCADE,
+    UNIQUE KEY (result_id, distance_meters)
+);
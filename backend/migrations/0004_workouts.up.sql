@@ -0,0 +1,16 @@
+-- Training log. Distance is kept in meters like races and courses; duration
+-- and perceived effort (1-10) are optional.
+CREATE TABLE workouts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    athlete_id INT NOT NULL,
+    date DATE NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    distance_meters INT NOT NULL,
+    duration_ms INT,
+    effort INT,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (athlete_id) REFERENCES athletes(id) ON DELETE CASCADE,
+    INDEX (athlete_id, date),
+    INDEX (date)
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS entries;
+
+ALTER TABLE races
+    DROP COLUMN entry_limit;
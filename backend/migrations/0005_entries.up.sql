@@ -0,0 +1,16 @@
+-- Meet entries declare who is running in which race before the meet. A race
+-- may cap how many athletes can be entered, e.g. seven for a varsity race.
+ALTER TABLE races
+    ADD COLUMN entry_limit INT AFTER distance_meters;
+
+CREATE TABLE entries (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    meet_id INT NOT NULL,
+    race_id INT NOT NULL,
+    athlete_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (meet_id) REFERENCES meets(id) ON DELETE CASCADE,
+    FOREIGN KEY (race_id) REFERENCES races(id) ON DELETE CASCADE,
+    FOREIGN KEY (athlete_id) REFERENCES athletes(id) ON DELETE CASCADE,
+    UNIQUE KEY (meet_id, athlete_id)
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS timing_taps;
+DROP TABLE IF EXISTS timing_sessions;
+
+ALTER TABLE entries
+    DROP INDEX entries_meet_bib,
+    DROP COLUMN bib;
@@ -0,0 +1,30 @@
+-- Finish-line timing. A session runs the clock for one race; each tap is a
+-- finisher crossing the line, timed by the server and matched to an athlete
+-- afterwards, by bib number or name, before the session becomes results.
+ALTER TABLE entries
+    ADD COLUMN bib INT AFTER athlete_id,
+    ADD UNIQUE KEY entries_meet_bib (meet_id, bib);
+
+CREATE TABLE timing_sessions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    meet_id INT NOT NULL,
+    race_id INT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'running',
+    started_at DATETIME(3) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (meet_id) REFERENCES meets(id) ON DELETE CASCADE,
+    FOREIGN KEY (race_id) REFERENCES races(id) ON DELETE CASCADE
+);
+
+CREATE TABLE timing_taps (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    session_id INT NOT NULL,
+    elapsed_ms INT NOT NULL,
+    athlete_id INT,
+    recorded_at DATETIME(3) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES timing_sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (athlete_id) REFERENCES athletes(id) ON DELETE SET NULL,
+    UNIQUE KEY (session_id, athlete_id),
+    INDEX (session_id, elapsed_ms)
+);
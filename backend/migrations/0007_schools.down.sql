@@ -0,0 +1,10 @@
+-- Opponent athletes have nowhere to belong without schools, so they are
+-- removed along with their results.
+DELETE FROM athletes
+WHERE school_id NOT IN (SELECT id FROM schools WHERE is_home);
+
+ALTER TABLE athletes
+    DROP FOREIGN KEY athletes_school,
+    DROP COLUMN school_id;
+
+DROP TABLE IF EXISTS schools;
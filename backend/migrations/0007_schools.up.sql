@@ -0,0 +1,21 @@
+-- Schools let results cover the whole field rather than only our own
+-- runners. Our own school is the home school, and every athlete recorded
+-- before schools existed belongs to it.
+CREATE TABLE schools (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    is_home BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO schools (name, is_home) VALUES ('Jones County', TRUE);
+
+ALTER TABLE athletes
+    ADD COLUMN school_id INT AFTER division;
+
+UPDATE athletes
+SET school_id = (SELECT id FROM schools WHERE is_home);
+
+ALTER TABLE athletes
+    MODIFY school_id INT NOT NULL,
+    ADD CONSTRAINT athletes_school FOREIGN KEY (school_id) REFERENCES schools(id);
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// Bounds used when a date range filter is left open; they span every date
+// MySQL can store.
+var (
+	earliestDate = time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC)
+	latestDate   = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+)
+
+// listQuery holds the paging and sorting parameters shared by list endpoints.
+type listQuery struct {
+	Page  int32
+	Limit int32
+	// Sort is the sort field, prefixed with "-" when descending, which is
+	// the form the list queries expect.
+	Sort string
+}
+
+func (q listQuery) Offset() int32 {
+	return (q.Page - 1) * q.Limit
+}
+
+// PageResponse is one page of a list endpoint. Next is the URL of the
+// following page, or null on the last one.
+type PageResponse[T any] struct {
+	Data  []T     `json:"data"`
+	Total int64   `json:"total"`
+	Page  int32   `json:"page"`
+	Limit int32   `json:"limit"`
+	Next  *string `json:"next"`
+}
+
+// parseListQuery reads ?page, ?limit, ?sort and ?order. The sort field must
+// be one of fields.
+func parseListQuery(c *gin.Context, fields []string, defaultSort string) (listQuery, error) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		return listQuery{}, badRequest("Invalid page")
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageLimit)))
+	if err != nil || limit <= 0 || limit > maxPageLimit {
+		return listQuery{}, badRequest(fmt.Sprintf("Invalid limit, use 1 to %d", maxPageLimit))
+	}
+	// The offset of the last page has to fit the queries' INT parameter.
+	if maxPage := math.MaxInt32 / limit; page > maxPage {
+		return listQuery{}, badRequest(fmt.Sprintf("Invalid page, use 1 to %d", maxPage))
+	}
+
+	sort := c.DefaultQuery("sort", defaultSort)
+	if !slices.Contains(fields, sort) {
+		return listQuery{}, badRequest("Invalid sort, use " + strings.Join(fields, ", "))
+	}
+	switch c.DefaultQuery("order", "asc") {
+	case "asc":
+	case "desc":
+		sort = "-" + sort
+	default:
+		return listQuery{}, badRequest("Invalid order, use asc or desc")
+	}
+
+	return listQuery{Page: int32(page), Limit: int32(limit), Sort: sort}, nil
+}
+
+func newPageResponse[T any](c *gin.Context, data []T, total int64, q listQuery) PageResponse[T] {
+	resp := PageResponse[T]{Data: data, Total: total, Page: q.Page, Limit: q.Limit}
+	if int64(q.Offset())+int64(len(data)) < total {
+		params := c.Request.URL.Query()
+		params.Set("page", strconv.Itoa(int(q.Page)+1))
+		next := c.Request.URL.Path + "?" + params.Encode()
+		resp.Next = &next
+	}
+	return resp
+}
+
+// likeEscaper escapes LIKE wildcards so user input matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern is a LIKE pattern matching values that contain s; an empty
+// s matches everything.
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+// equalsPattern is a LIKE pattern matching s exactly; an empty s matches
+// everything.
+func equalsPattern(s string) string {
+	if s == "" {
+		return "%"
+	}
+	return likeEscaper.Replace(s)
+}
+
+// dateQuery reads an optional YYYY-MM-DD query parameter.
+func dateQuery(c *gin.Context, key string, fallback time.Time) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return fallback, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, badRequest(fmt.Sprintf("Invalid %s date, use YYYY-MM-DD", key))
+	}
+	return t, nil
+}
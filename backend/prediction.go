@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"net/http"
+	"strconv"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// predictionRaces is how many of an athlete's latest finishes a prediction
+// considers. The one showing the best fitness is used.
+const predictionRaces = 5
+
+// calculatorDistances are the distances the calculator converts to when no
+// target distance is given.
+var calculatorDistances = []int32{1600, 3200, 4000, 5000, 6000, 8000, 10000}
+
+// EquivalentTime is a predicted time at one distance by both methods, with
+// the mile pace of the VDOT prediction.
+type EquivalentTime struct {
+	DistanceMeters int32    `json:"distanceMeters"`
+	Riegel         RaceTime `json:"riegel"`
+	VDOT           RaceTime `json:"vdot"`
+	PacePerMile    RaceTime `json:"pacePerMile"`
+}
+
+type CalculatorResponse struct {
+	DistanceMeters int32            `json:"distanceMeters"`
+	Time           RaceTime         `json:"time"`
+	VDOT           float64          `json:"vdot"`
+	Equivalents    []EquivalentTime `json:"equivalents"`
+}
+
+// PredictionBasis is the result a prediction was made from.
+type PredictionBasis struct {
+	ResultID       int32    `json:"resultId"`
+	MeetID         int32    `json:"meetId"`
+	MeetName       string   `json:"meetName"`
+	MeetDate       string   `json:"meetDate"`
+	DistanceMeters int32    `json:"distanceMeters"`
+	Time           RaceTime `json:"time"`
+	VDOT           float64  `json:"vdot"`
+}
+
+// PredictionResponse predicts an athlete's time at a distance, or in a race
+// at an upcoming meet. TargetPace breaks the VDOT prediction into even mile
+// splits.
+type PredictionResponse struct {
+	AthleteID   int32           `json:"athleteId"`
+	AthleteName string          `json:"athleteName"`
+	MeetID      int32           `json:"meetId,omitempty"`
+	RaceID      int32           `json:"raceId,omitempty"`
+	RaceName    string          `json:"raceName,omitempty"`
+	Basis       PredictionBasis `json:"basis"`
+	Prediction  EquivalentTime  `json:"prediction"`
+	TargetPace  []SplitResponse `json:"targetPace"`
+}
+
+// vdot estimates the aerobic fitness shown by a race using Daniels and
+// Gilbert's formulas, which the published VDOT tables are built from.
+func vdot(t RaceTime, distanceMeters int32) float64 {
+	minutes := t.Seconds() / 60
+	velocity := float64(distanceMeters) / minutes
+	vo2 := -4.60 + 0.182258*velocity + 0.000104*velocity*velocity
+	percentMax := 0.8 + 0.1894393*math.Exp(-0.012778*minutes) + 0.2989558*math.Exp(-0.1932605*minutes)
+	return vo2 / percentMax
+}
+
+// vdotTime finds the time at a distance that matches a VDOT, rounded to the
+// nearest second. VDOT falls as the time grows, so a bisection converges.
+func vdotTime(fitness float64, distanceMeters int32) RaceTime {
+	lo, hi := RaceTime(1000), RaceTime(maxRaceTimeMs)
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if vdot(mid, distanceMeters) > fitness {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return RaceTime(math.Round(float64(hi)/1000) * 1000)
+}
+
+// equivalentTime predicts the time at toMeters for a race of t over
+// fromMeters.
+func equivalentTime(t RaceTime, fromMeters, toMeters int32) EquivalentTime {
+	predicted := vdotTime(vdot(t, fromMeters), toMeters)
+	return EquivalentTime{
+		DistanceMeters: toMeters,
+		Riegel:         t.Equivalent(fromMeters, toMeters),
+		VDOT:           predicted,
+		PacePerMile:    predicted.Pace(toMeters, metersPerMile),
+	}
+}
+
+// targetPace splits a goal time into even mile checkpoints and the finish.
+func targetPace(goal RaceTime, distanceMeters int32) []SplitResponse {
+	var checkpoints []checkpoint
+	for mile := 1; float64(mile)*metersPerMile < float64(distanceMeters); mile++ {
+		distance := int32(math.Round(float64(mile) * metersPerMile))
+		elapsed := float64(goal) * float64(distance) / float64(distanceMeters)
+		checkpoints = append(checkpoints, checkpoint{
+			Distance: distance,
+			Elapsed:  RaceTime(math.Round(elapsed/1000) * 1000),
+		})
+	}
+	checkpoints = append(checkpoints, checkpoint{Distance: distanceMeters, Elapsed: goal})
+	segments, _ := analyzeSplits(checkpoints)
+	return segments
+}
+
+// roundVDOT rounds a VDOT to one decimal place, as the tables give it.
+func roundVDOT(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// predictionBasis picks the best performance, by VDOT, among an athlete's
+// latest finishes in date order.
+func predictionBasis(rows []db.GetAthleteHistoryRow) PredictionBasis {
+	if len(rows) > predictionRaces {
+		rows = rows[len(rows)-predictionRaces:]
+	}
+	var basis PredictionBasis
+	for _, r := range rows {
+		t := RaceTime(r.TimeMs.Int32)
+		if v := vdot(t, r.DistanceMeters); v > basis.VDOT {
+			basis = PredictionBasis{
+				ResultID:       r.ID,
+				MeetID:         r.MeetID,
+				MeetName:       r.MeetName,
+				MeetDate:       r.MeetDate.Format("2006-01-02"),
+				DistanceMeters: r.DistanceMeters,
+				Time:           t,
+				VDOT:           v,
+			}
+		}
+	}
+	return basis
+}
+
+// distanceQuery reads an optional race distance in meters.
+func distanceQuery(c *gin.Context, key string, fallback int32) (int32, error) {
+	value := c.Query(key)
+	if value == "" {
+		return fallback, nil
+	}
+	distance, err := strconv.Atoi(value)
+	if err != nil || distance <= 0 {
+		return 0, badRequest("Invalid distance")
+	}
+	return int32(distance), nil
+}
+
+func registerPredictionRoutes(r *gin.Engine) {
+	// Predict an athlete's time from their recent results, either at ?distance
+	// (5000m by default) or in a race at an upcoming meet with ?meetId and,
+	// when the meet has several races, ?raceId
+	r.GET("/api/athletes/:id/prediction", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid athlete ID"})
+			return
+		}
+
+		athlete, err := queries.GetAthleteByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Athlete not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := PredictionResponse{AthleteID: athlete.ID, AthleteName: athlete.Name}
+		distance, err := distanceQuery(c, "distance", defaultDistanceMeters)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if m := c.Query("meetId"); m != "" {
+			meetID, err := strconv.Atoi(m)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meet ID"})
+				return
+			}
+			raceID, err := strconv.Atoi(c.DefaultQuery("raceId", "0"))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid race ID"})
+				return
+			}
+			race, err := resolveRace(context.Background(), queries, int32(meetID), int32(raceID))
+			if err != nil {
+				c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+			response.MeetID, response.RaceID, response.RaceName = race.MeetID, race.ID, race.Name
+			distance = race.DistanceMeters
+		}
+
+		rows, err := queries.GetAthleteHistory(context.Background(), athlete.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		finished := finishedRows(rows)
+		if len(finished) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Athlete has no finished results to predict from"})
+			return
+		}
+
+		response.Basis = predictionBasis(finished)
+		response.Prediction = equivalentTime(response.Basis.Time, response.Basis.DistanceMeters, distance)
+		response.TargetPace = targetPace(response.Prediction.VDOT, distance)
+		response.Basis.VDOT = roundVDOT(response.Basis.VDOT)
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Convert any ?time at ?distance to equivalent times at ?to, or at the
+	// common race distances when ?to is left out
+	r.GET("/api/calculator", func(c *gin.Context) {
+		t, err := ParseRaceTime(c.Query("time"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		distance, err := distanceQuery(c, "distance", defaultDistanceMeters)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		targets := calculatorDistances
+		if c.Query("to") != "" {
+			to, err := distanceQuery(c, "to", 0)
+			if err != nil {
+				c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+			targets = []int32{to}
+		}
+
+		response := CalculatorResponse{
+			DistanceMeters: distance,
+			Time:           t,
+			VDOT:           roundVDOT(vdot(t, distance)),
+			Equivalents:    make([]EquivalentTime, len(targets)),
+		}
+		for i, to := range targets {
+			response.Equivalents[i] = equivalentTime(t, distance, to)
+		}
+		c.JSON(http.StatusOK, response)
+	})
+}
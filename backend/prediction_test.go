@@ -0,0 +1,74 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// Times from the published VDOT tables.
+var vdotTable = []struct {
+	vdot     float64
+	distance int32
+	time     string
+}{
+	{40, 5000, "24:08"},
+	{50, 5000, "19:57"},
+	{60, 5000, "17:03"},
+	{50, 10000, "41:21"},
+}
+
+func TestVDOT(t *testing.T) {
+	for _, tt := range vdotTable {
+		raceTime, err := ParseRaceTime(tt.time)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := vdot(raceTime, tt.distance); math.Abs(got-tt.vdot) > 0.2 {
+			t.Errorf("vdot(%s, %d) = %.2f, want about %v", tt.time, tt.distance, got, tt.vdot)
+		}
+	}
+}
+
+func TestVDOTTime(t *testing.T) {
+	for _, tt := range vdotTable {
+		want, _ := ParseRaceTime(tt.time)
+		got := vdotTime(tt.vdot, tt.distance)
+		if diff := got - want; diff < -3000 || diff > 3000 {
+			t.Errorf("vdotTime(%v, %d) = %s, want about %s", tt.vdot, tt.distance, got, tt.time)
+		}
+		if got%1000 != 0 {
+			t.Errorf("vdotTime(%v, %d) = %s, want whole seconds", tt.vdot, tt.distance, got)
+		}
+	}
+
+	// Converting a race to its own distance gives the same time back.
+	for _, s := range []string{"15:30", "16:45", "19:02", "24:40"} {
+		t5k, _ := ParseRaceTime(s)
+		if got := vdotTime(vdot(t5k, 5000), 5000); got != t5k {
+			t.Errorf("vdotTime(vdot(%s)) = %s", s, got)
+		}
+	}
+
+	// Fitter runners are faster.
+	if vdotTime(55, 5000) >= vdotTime(50, 5000) {
+		t.Errorf("VDOT 55 is not faster than VDOT 50")
+	}
+}
+
+func TestEquivalentTime(t *testing.T) {
+	t5k, _ := ParseRaceTime("19:57")
+	got := equivalentTime(t5k, 5000, 10000)
+	if got.DistanceMeters != 10000 {
+		t.Errorf("distance = %d", got.DistanceMeters)
+	}
+	want, _ := ParseRaceTime("41:21")
+	if diff := got.VDOT - want; diff < -5000 || diff > 5000 {
+		t.Errorf("VDOT prediction = %s, want about %s", got.VDOT, want)
+	}
+	if got.Riegel != t5k.Equivalent(5000, 10000) {
+		t.Errorf("Riegel prediction = %s, want %s", got.Riegel, t5k.Equivalent(5000, 10000))
+	}
+	if got.PacePerMile != got.VDOT.Pace(10000, metersPerMile) {
+		t.Errorf("pace = %s, want %s", got.PacePerMile, got.VDOT.Pace(10000, metersPerMile))
+	}
+}
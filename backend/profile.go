@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AthleteHistoryResult is one race in an athlete's history, with its pacing
+// and whether it was a record at the time.
+type AthleteHistoryResult struct {
+	ID             int32    `json:"id"`
+	Status         string   `json:"status"`
+	MeetID         int32    `json:"meetId"`
+	MeetName       string   `json:"meetName"`
+	MeetDate       string   `json:"meetDate"`
+	CourseID       int32    `json:"courseId"`
+	CourseName     string   `json:"courseName"`
+	RaceID         int32    `json:"raceId"`
+	RaceName       string   `json:"raceName"`
+	Grade          int32    `json:"grade"`
+	Time           RaceTime `json:"time"`
+	Place          int32    `json:"place"`
+	PersonalRecord bool     `json:"personalRecord"`
+	SeasonBest     bool     `json:"seasonBest"`
+	Pacing
+}
+
+// SeasonBestResponse is an athlete's fastest mark at one distance in one
+// season. Meets outside any season are grouped by calendar year.
+type SeasonBestResponse struct {
+	SeasonID       int32    `json:"seasonId"`
+	Season         string   `json:"season"`
+	DistanceMeters int32    `json:"distanceMeters"`
+	Time           RaceTime `json:"time"`
+	ResultID       int32    `json:"resultId"`
+	MeetID         int32    `json:"meetId"`
+	MeetName       string   `json:"meetName"`
+	MeetDate       string   `json:"meetDate"`
+}
+
+// DistanceStats summarizes every race an athlete has run at one distance.
+// Improvement compares the first race with the latest; a positive value means
+// the athlete got faster.
+type DistanceStats struct {
+	DistanceMeters     int32    `json:"distanceMeters"`
+	Races              int      `json:"races"`
+	Best               RaceTime `json:"best"`
+	Average            RaceTime `json:"average"`
+	Median             RaceTime `json:"median"`
+	First              RaceTime `json:"first"`
+	Latest             RaceTime `json:"latest"`
+	ImprovementSeconds float64  `json:"improvementSeconds"`
+	ImprovementPercent float64  `json:"improvementPercent"`
+}
+
+type AthleteProfileResponse struct {
+	AthleteResponse
+	Results     []AthleteHistoryResult `json:"results"`
+	SeasonBests []SeasonBestResponse   `json:"seasonBests"`
+	Stats       []DistanceStats        `json:"stats"`
+}
+
+// finishedRows drops the races an athlete did not finish from a history.
+func finishedRows(rows []db.GetAthleteHistoryRow) []db.GetAthleteHistoryRow {
+	var finished []db.GetAthleteHistoryRow
+	for _, r := range rows {
+		if r.Status == statusFinished {
+			finished = append(finished, r)
+		}
+	}
+	return finished
+}
+
+func marksFromHistoryRows(athleteID int32, rows []db.GetAthleteHistoryRow) []mark {
+	marks := make([]mark, len(rows))
+	for i, r := range rows {
+		marks[i] = mark{
+			ResultID:  r.ID,
+			AthleteID: athleteID,
+			Distance:  r.DistanceMeters,
+			Time:      RaceTime(r.TimeMs.Int32),
+			MeetID:    r.MeetID,
+			MeetName:  r.MeetName,
+			MeetDate:  r.MeetDate,
+			SeasonID:  r.SeasonID,
+		}
+	}
+	return marks
+}
+
+// seasonBests picks the fastest mark per season and distance from a history
+// in date order, newest season first.
+func seasonBests(rows []db.GetAthleteHistoryRow) []SeasonBestResponse {
+	// Meets outside a season are keyed by year, negated so they cannot
+	// collide with a season ID.
+	type key struct {
+		season   int32
+		distance int32
+	}
+	best := make(map[key]db.GetAthleteHistoryRow)
+	seasonOrder := make(map[int32]int)
+	var keys []key
+	for _, r := range rows {
+		season := -int32(r.MeetDate.Year())
+		if r.SeasonID.Valid {
+			season = r.SeasonID.Int32
+		}
+		if _, ok := seasonOrder[season]; !ok {
+			seasonOrder[season] = len(seasonOrder)
+		}
+		k := key{season, r.DistanceMeters}
+		b, ok := best[k]
+		if !ok {
+			keys = append(keys, k)
+		}
+		if !ok || r.TimeMs.Int32 < b.TimeMs.Int32 {
+			best[k] = r
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].season != keys[j].season {
+			return seasonOrder[keys[i].season] > seasonOrder[keys[j].season]
+		}
+		return keys[i].distance < keys[j].distance
+	})
+
+	response := make([]SeasonBestResponse, len(keys))
+	for i, k := range keys {
+		r := best[k]
+		season := strconv.Itoa(r.MeetDate.Year())
+		if r.SeasonName.Valid {
+			season = r.SeasonName.String
+		}
+		response[i] = SeasonBestResponse{
+			SeasonID:       r.SeasonID.Int32,
+			Season:         season,
+			DistanceMeters: k.distance,
+			Time:           RaceTime(r.TimeMs.Int32),
+			ResultID:       r.ID,
+			MeetID:         r.MeetID,
+			MeetName:       r.MeetName,
+			MeetDate:       r.MeetDate.Format("2006-01-02"),
+		}
+	}
+	return response
+}
+
+// distanceStats summarizes a history in date order, one entry per distance.
+func distanceStats(rows []db.GetAthleteHistoryRow) []DistanceStats {
+	byDistance := make(map[int32][]RaceTime)
+	for _, r := range rows {
+		byDistance[r.DistanceMeters] = append(byDistance[r.DistanceMeters], RaceTime(r.TimeMs.Int32))
+	}
+
+	stats := make([]DistanceStats, 0, len(byDistance))
+	for distance, times := range byDistance {
+		sorted := append([]RaceTime(nil), times...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		var total int64
+		for _, t := range times {
+			total += int64(t)
+		}
+		n := len(sorted)
+		median := sorted[n/2]
+		if n%2 == 0 {
+			median = (sorted[n/2-1] + sorted[n/2]) / 2
+		}
+
+		first, latest := times[0], times[n-1]
+		improvement := (first - latest).Seconds()
+		stats = append(stats, DistanceStats{
+			DistanceMeters:     distance,
+			Races:              n,
+			Best:               sorted[0],
+			Average:            RaceTime(total / int64(n)),
+			Median:             median,
+			First:              first,
+			Latest:             latest,
+			ImprovementSeconds: improvement,
+			ImprovementPercent: math.Round(improvement/first.Seconds()*1000) / 10,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].DistanceMeters < stats[j].DistanceMeters
+	})
+	return stats
+}
+
+func registerProfileRoutes(r *gin.Engine) {
+	// Get an athlete with their full race history, records and statistics
+	r.GET("/api/athletes/:id/profile", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid athlete ID"})
+			return
+		}
+
+		athlete, err := queries.GetAthleteByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Athlete not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		rows, err := queries.GetAthleteHistory(context.Background(), athlete.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		finished := finishedRows(rows)
+		marks := marksFromHistoryRows(athlete.ID, finished)
+		byResult := make(map[int32]mark, len(marks))
+		for _, m := range marks {
+			byResult[m.ResultID] = m
+		}
+
+		results := make([]AthleteHistoryResult, len(rows))
+		for i, r := range rows {
+			var pr, sb bool
+			if m, ok := byResult[r.ID]; ok {
+				pr, sb = recordFlags(marks, m)
+			}
+			results[i] = AthleteHistoryResult{
+				ID:             r.ID,
+				Status:         r.Status,
+				MeetID:         r.MeetID,
+				MeetName:       r.MeetName,
+				MeetDate:       r.MeetDate.Format("2006-01-02"),
+				CourseID:       r.CourseID.Int32,
+				CourseName:     r.CourseName.String,
+				RaceID:         r.RaceID,
+				RaceName:       r.RaceName,
+				Grade:          gradeAt(athlete.Grade, r.SeasonGrade),
+				Time:           RaceTime(r.TimeMs.Int32),
+				Place:          r.Place.Int32,
+				Pacing:         newPacing(RaceTime(r.TimeMs.Int32), r.DistanceMeters),
+				PersonalRecord: pr,
+				SeasonBest:     sb,
+			}
+		}
+
+		c.JSON(http.StatusOK, AthleteProfileResponse{
+			AthleteResponse: newAthleteResponse(athlete, personalRecords(marks)),
+			Results:         results,
+			SeasonBests:     seasonBests(finished),
+			Stats:           distanceStats(finished),
+		})
+	})
+}
@@ -1,62 +1,198 @@
 -- name: GetAllAthletes :many
-SELECT id, name, grade, personal_record, events, created_at
+SELECT id, name, grade, division, school_id, status, events, created_at
 FROM athletes
 ORDER BY name;
 
+-- name: ListAthletes :many
+SELECT id, name, grade, division, school_id, status, events, created_at
+FROM athletes
+WHERE school_id = sqlc.arg(school_id)
+  AND grade >= sqlc.arg(min_grade) AND grade <= sqlc.arg(max_grade)
+  AND division LIKE sqlc.arg(division) AND status LIKE sqlc.arg(status)
+  AND name LIKE sqlc.arg(name)
+ORDER BY
+  CASE WHEN sqlc.arg(sort) = 'name' THEN name END ASC,
+  CASE WHEN sqlc.arg(sort) = '-name' THEN name END DESC,
+  CASE WHEN sqlc.arg(sort) = 'grade' THEN grade END ASC,
+  CASE WHEN sqlc.arg(sort) = '-grade' THEN grade END DESC,
+  name, id
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountAthletes :one
+SELECT COUNT(*)
+FROM athletes
+WHERE school_id = sqlc.arg(school_id)
+  AND grade >= sqlc.arg(min_grade) AND grade <= sqlc.arg(max_grade)
+  AND division LIKE sqlc.arg(division) AND status LIKE sqlc.arg(status)
+  AND name LIKE sqlc.arg(name);
+
 -- name: GetAthleteByID :one
-SELECT id, name, grade, personal_record, events, created_at
+SELECT id, name, grade, division, school_id, status, events, created_at
 FROM athletes
 WHERE id = ?;
 
 -- name: GetAllMeets :many
-SELECT id, name, date, location, description, created_at
+SELECT id, name, date, location, course_id, season_id, description, created_at
 FROM meets
 ORDER BY date;
 
+-- name: ListMeets :many
+SELECT id, name, date, location, course_id, season_id, description, created_at
+FROM meets
+WHERE date >= sqlc.arg(from_date) AND date <= sqlc.arg(to_date)
+  AND location LIKE sqlc.arg(location) AND name LIKE sqlc.arg(name)
+ORDER BY
+  CASE WHEN sqlc.arg(sort) = 'date' THEN date END ASC,
+  CASE WHEN sqlc.arg(sort) = '-date' THEN date END DESC,
+  CASE WHEN sqlc.arg(sort) = 'name' THEN name END ASC,
+  CASE WHEN sqlc.arg(sort) = '-name' THEN name END DESC,
+  id
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountMeets :one
+SELECT COUNT(*)
+FROM meets
+WHERE date >= sqlc.arg(from_date) AND date <= sqlc.arg(to_date)
+  AND location LIKE sqlc.arg(location) AND name LIKE sqlc.arg(name);
+
+-- name: GetMeetByID :one
+SELECT id, name, date, location, course_id, season_id, description, created_at
+FROM meets
+WHERE id = ?;
+
 -- name: GetResultsByMeetID :many
-SELECT r.id, r.athlete_id, r.meet_id, r.time, r.place, r.created_at
+SELECT r.id, r.athlete_id, r.meet_id, r.race_id, r.status, r.time_ms, r.place, r.created_at
 FROM results r
 WHERE r.meet_id = ?
-ORDER BY r.place;
+ORDER BY r.race_id, r.place IS NULL, r.place;
 
 -- name: GetMeetResults :many
-SELECT r.id, r.time, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade
+SELECT r.id, r.status, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, sc.id AS school_id, sc.name AS school_name, ra.id AS race_id, ra.name AS race_name
 FROM results r
 JOIN athletes a ON r.athlete_id = a.id
+JOIN schools sc ON a.school_id = sc.id
+JOIN races ra ON r.race_id = ra.id
+JOIN meets m ON r.meet_id = m.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
 WHERE r.meet_id = ?
-ORDER BY r.place;
+ORDER BY ra.start_time, ra.id, r.place IS NULL, r.place;
+
+-- name: ListMeetResults :many
+SELECT r.id, r.status, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, sc.id AS school_id, sc.name AS school_name, ra.id AS race_id, ra.name AS race_name, ra.distance_meters
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN schools sc ON a.school_id = sc.id
+JOIN races ra ON r.race_id = ra.id
+JOIN meets m ON r.meet_id = m.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+WHERE r.meet_id = sqlc.arg(meet_id)
+  AND r.race_id >= sqlc.arg(min_race_id) AND r.race_id <= sqlc.arg(max_race_id)
+  AND a.division LIKE sqlc.arg(division) AND a.name LIKE sqlc.arg(name)
+ORDER BY ra.start_time, ra.id, r.place IS NULL,
+  CASE WHEN sqlc.arg(sort) = 'place' THEN r.place END ASC,
+  CASE WHEN sqlc.arg(sort) = '-place' THEN r.place END DESC,
+  CASE WHEN sqlc.arg(sort) = 'time' THEN r.time_ms END ASC,
+  CASE WHEN sqlc.arg(sort) = '-time' THEN r.time_ms END DESC,
+  CASE WHEN sqlc.arg(sort) = 'name' THEN a.name END ASC,
+  CASE WHEN sqlc.arg(sort) = '-name' THEN a.name END DESC,
+  r.id
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountMeetResults :one
+SELECT COUNT(*)
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+WHERE r.meet_id = sqlc.arg(meet_id)
+  AND r.race_id >= sqlc.arg(min_race_id) AND r.race_id <= sqlc.arg(max_race_id)
+  AND a.division LIKE sqlc.arg(division) AND a.name LIKE sqlc.arg(name);
+
+-- name: GetMeetResult :one
+SELECT r.id, r.meet_id, r.status, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, sc.id AS school_id, sc.name AS school_name, ra.id AS race_id, ra.name AS race_name, ra.distance_meters
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN schools sc ON a.school_id = sc.id
+JOIN races ra ON r.race_id = ra.id
+JOIN meets m ON r.meet_id = m.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+WHERE r.id = ?;
+
+-- name: GetRaceResults :many
+SELECT r.id, r.status, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, sc.id AS school_id, sc.name AS school_name
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN schools sc ON a.school_id = sc.id
+JOIN meets m ON r.meet_id = m.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+WHERE r.race_id = ?
+ORDER BY r.place IS NULL, r.place;
 
 -- name: CreateResult :execresult
-INSERT INTO results (athlete_id, meet_id, time, place)
-VALUES (?, ?, ?, ?);
+INSERT INTO results (athlete_id, meet_id, race_id, status, time_ms, place)
+VALUES (?, ?, ?, ?, ?, ?);
 
 -- name: GetTopTimes :many
-SELECT r.id, r.time, r.place, a.id AS athlete_id, a.name AS athlete_name, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date
+SELECT r.id, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date, ra.id AS race_id, ra.name AS race_name
 FROM results r
 JOIN athletes a ON r.athlete_id = a.id
 JOIN meets m ON r.meet_id = m.id
-ORDER BY r.time ASC
-LIMIT 10;
+JOIN races ra ON r.race_id = ra.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+WHERE r.status = 'finished'
+  AND ra.distance_meters = sqlc.arg(distance_meters) AND a.division = sqlc.arg(division)
+  AND a.school_id = sqlc.arg(school_id)
+  AND m.date >= sqlc.arg(from_date) AND m.date <= sqlc.arg(to_date)
+  AND (sqlc.narg(season_id) IS NULL OR m.season_id = sqlc.narg(season_id))
+  AND (sqlc.narg(course_id) IS NULL OR m.course_id = sqlc.narg(course_id))
+  AND (sqlc.narg(grade) IS NULL OR sr.grade = sqlc.narg(grade) OR (sr.grade IS NULL AND a.grade = sqlc.narg(grade)))
+ORDER BY r.time_ms ASC, m.date ASC, r.id
+LIMIT sqlc.arg(limit);
+
+-- name: GetResultMarksForAthletes :many
+SELECT r.id, r.athlete_id, r.time_ms, ra.distance_meters, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date, m.season_id
+FROM results r
+JOIN races ra ON r.race_id = ra.id
+JOIN meets m ON r.meet_id = m.id
+WHERE r.status = 'finished' AND r.athlete_id IN (sqlc.slice(athlete_ids))
+ORDER BY m.date, r.id;
+
+-- name: GetAthleteResultMarks :many
+SELECT r.id, r.athlete_id, r.time_ms, ra.distance_meters, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date, m.season_id
+FROM results r
+JOIN races ra ON r.race_id = ra.id
+JOIN meets m ON r.meet_id = m.id
+WHERE r.athlete_id = ? AND r.status = 'finished'
+ORDER BY m.date, r.id;
+
+-- name: GetAthleteHistory :many
+SELECT r.id, r.status, r.time_ms, r.place, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date, m.season_id, s.name AS season_name, c.id AS course_id, c.name AS course_name, ra.id AS race_id, ra.name AS race_name, ra.distance_meters, sr.grade AS season_grade
+FROM results r
+JOIN meets m ON r.meet_id = m.id
+JOIN races ra ON r.race_id = ra.id
+LEFT JOIN seasons s ON m.season_id = s.id
+LEFT JOIN courses c ON m.course_id = c.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = r.athlete_id
+WHERE r.athlete_id = ?
+ORDER BY m.date, r.id;
 
 -- name: CreateAthlete :execresult
-INSERT INTO athletes (name, grade, personal_record, events)
-VALUES (?, ?, ?, ?);
+INSERT INTO athletes (name, grade, division, school_id, events)
+VALUES (?, ?, ?, ?, ?);
 
 -- name: UpdateAthlete :exec
 UPDATE athletes
-SET name = ?, grade = ?, personal_record = ?, events = ?
+SET name = ?, grade = ?, division = ?, school_id = ?, events = ?
 WHERE id = ?;
 
 -- name: DeleteAthlete :exec
 DELETE FROM athletes WHERE id = ?;
 
 -- name: CreateMeet :execresult
-INSERT INTO meets (name, date, location, description)
-VALUES (?, ?, ?, ?);
+INSERT INTO meets (name, date, location, course_id, season_id, description)
+VALUES (?, ?, ?, ?, ?, ?);
 
 -- name: UpdateMeet :exec
 UPDATE meets
-SET name = ?, date = ?, location = ?, description = ?
+SET name = ?, date = ?, location = ?, course_id = ?, season_id = ?, description = ?
 WHERE id = ?;
 
 -- name: DeleteMeet :exec
@@ -64,8 +200,385 @@ DELETE FROM meets WHERE id = ?;
 
 -- name: UpdateResult :exec
 UPDATE results
-SET athlete_id = ?, meet_id = ?, time = ?, place = ?
+SET athlete_id = ?, meet_id = ?, race_id = ?, status = ?, time_ms = ?, place = ?
 WHERE id = ?;
 
 -- name: DeleteResult :exec
 DELETE FROM results WHERE id = ?;
+
+-- name: GetRacesByMeetID :many
+SELECT id, meet_id, name, distance_meters, entry_limit, start_time, created_at
+FROM races
+WHERE meet_id = ?
+ORDER BY start_time, id;
+
+-- name: GetRaceByID :one
+SELECT id, meet_id, name, distance_meters, entry_limit, start_time, created_at
+FROM races
+WHERE id = ?;
+
+-- name: GetRaceForUpdate :one
+SELECT id, meet_id, name, distance_meters, entry_limit, start_time, created_at
+FROM races
+WHERE id = ?
+FOR UPDATE;
+
+-- name: CreateRace :execresult
+INSERT INTO races (meet_id, name, distance_meters, entry_limit, start_time)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: UpdateRace :exec
+UPDATE races
+SET name = ?, distance_meters = ?, entry_limit = ?, start_time = ?
+WHERE id = ?;
+
+-- name: DeleteRace :exec
+DELETE FROM races WHERE id = ?;
+
+-- name: GetAllCourses :many
+SELECT id, name, venue, distance_meters, surface, latitude, longitude, notes, created_at
+FROM courses
+ORDER BY name;
+
+-- name: GetCourseByID :one
+SELECT id, name, venue, distance_meters, surface, latitude, longitude, notes, created_at
+FROM courses
+WHERE id = ?;
+
+-- name: CreateCourse :execresult
+INSERT INTO courses (name, venue, distance_meters, surface, latitude, longitude, notes)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: UpdateCourse :exec
+UPDATE courses
+SET name = ?, venue = ?, distance_meters = ?, surface = ?, latitude = ?, longitude = ?, notes = ?
+WHERE id = ?;
+
+-- name: DeleteCourse :exec
+DELETE FROM courses WHERE id = ?;
+
+-- name: GetCourseResults :many
+SELECT r.id, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, a.division AS athlete_division, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date, ra.id AS race_id, ra.name AS race_name, ra.distance_meters
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN meets m ON r.meet_id = m.id
+JOIN races ra ON r.race_id = ra.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+WHERE m.course_id = sqlc.arg(course_id) AND r.status = 'finished'
+  AND (sqlc.narg(school_id) IS NULL OR a.school_id = sqlc.narg(school_id))
+ORDER BY m.date, ra.id, r.place;
+
+-- name: GetCourseBestTimes :many
+SELECT r.id, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, m.id AS meet_id, m.name AS meet_name, m.date AS meet_date, ra.id AS race_id, ra.name AS race_name
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN meets m ON r.meet_id = m.id
+JOIN races ra ON r.race_id = ra.id
+WHERE m.course_id = ? AND ra.distance_meters = ? AND a.division = ? AND a.school_id = ? AND r.status = 'finished'
+ORDER BY r.time_ms ASC
+LIMIT ?;
+
+-- name: GetAllSeasons :many
+SELECT id, name, year, start_date, end_date, created_at
+FROM seasons
+ORDER BY year DESC;
+
+-- name: GetSeasonByID :one
+SELECT id, name, year, start_date, end_date, created_at
+FROM seasons
+WHERE id = ?;
+
+-- name: GetSeasonForDate :one
+SELECT id, name, year, start_date, end_date, created_at
+FROM seasons
+WHERE start_date <= ? AND end_date >= ?
+LIMIT 1;
+
+-- name: GetLatestSeason :one
+SELECT id, name, year, start_date, end_date, created_at
+FROM seasons
+ORDER BY year DESC
+LIMIT 1;
+
+-- name: GetPreviousSeason :one
+SELECT id, name, year, start_date, end_date, created_at
+FROM seasons
+WHERE year < ?
+ORDER BY year DESC
+LIMIT 1;
+
+-- name: CreateSeason :execresult
+INSERT INTO seasons (name, year, start_date, end_date)
+VALUES (?, ?, ?, ?);
+
+-- name: UpdateSeason :exec
+UPDATE seasons
+SET name = ?, year = ?, start_date = ?, end_date = ?
+WHERE id = ?;
+
+-- name: DeleteSeason :exec
+DELETE FROM seasons WHERE id = ?;
+
+-- name: GetSeasonMeets :many
+SELECT id, name, date, location, course_id, season_id, description, created_at
+FROM meets
+WHERE season_id = ?
+ORDER BY date;
+
+-- name: GetSeasonRoster :many
+SELECT a.id, a.name, sr.grade, a.division, a.status
+FROM season_rosters sr
+JOIN athletes a ON sr.athlete_id = a.id
+WHERE sr.season_id = ?
+ORDER BY a.division, a.name;
+
+-- name: CountSeasonRoster :one
+SELECT COUNT(*) FROM season_rosters WHERE season_id = ?;
+
+-- name: AddRosterMember :exec
+INSERT INTO season_rosters (season_id, athlete_id, grade)
+VALUES (?, ?, ?);
+
+-- name: AddRosterMemberIfMissing :exec
+INSERT IGNORE INTO season_rosters (season_id, athlete_id, grade)
+VALUES (?, ?, ?);
+
+-- name: RemoveRosterMember :exec
+DELETE FROM season_rosters WHERE season_id = ? AND athlete_id = ?;
+
+-- name: GetActiveAthletes :many
+SELECT id, name, grade, division, school_id, status, events, created_at
+FROM athletes
+WHERE status = 'active' AND school_id IN (SELECT id FROM schools WHERE is_home)
+ORDER BY name;
+
+-- name: GraduateSeniors :execrows
+UPDATE athletes
+SET status = 'alumni'
+WHERE status = 'active' AND grade >= 12
+  AND school_id IN (SELECT id FROM schools WHERE is_home);
+
+-- name: PromoteActiveAthletes :execrows
+UPDATE athletes
+SET grade = grade + 1
+WHERE status = 'active' AND school_id IN (SELECT id FROM schools WHERE is_home);
+
+-- name: GetAllUsers :many
+SELECT id, username, password_hash, role, created_at FROM users ORDER BY username;
+
+-- name: GetUserByID :one
+SELECT id, username, password_hash, role, created_at FROM users WHERE id = ?;
+
+-- name: GetUserByUsername :one
+SELECT id, username, password_hash, role, created_at FROM users WHERE username = ?;
+
+-- name: CreateUser :execresult
+INSERT INTO users (username, password_hash, role) VALUES (?, ?, ?);
+
+-- name: UpdateUserRole :exec
+UPDATE users SET role = ? WHERE id = ?;
+
+-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = ? WHERE id = ?;
+
+-- name: DeleteUser :exec
+DELETE FROM users WHERE id = ?;
+
+-- name: CreateSession :exec
+INSERT INTO sessions (token_hash, user_id, expires_at) VALUES (?, ?, ?);
+
+-- name: GetSessionUser :one
+SELECT u.id, u.username, u.role, s.expires_at
+FROM sessions s
+JOIN users u ON s.user_id = u.id
+WHERE s.token_hash = ?;
+
+-- name: DeleteSession :exec
+DELETE FROM sessions WHERE token_hash = ?;
+
+-- name: DeleteUserSessions :exec
+DELETE FROM sessions WHERE user_id = ?;
+
+-- name: DeleteExpiredSessions :exec
+DELETE FROM sessions WHERE expires_at < ?;
+
+-- name: GetResultRace :one
+SELECT r.id, r.status, r.time_ms, ra.id AS race_id, ra.distance_meters
+FROM results r
+JOIN races ra ON r.race_id = ra.id
+WHERE r.id = ?;
+
+-- name: GetResultSplits :many
+SELECT id, result_id, distance_meters, elapsed_ms, created_at
+FROM splits
+WHERE result_id = ?
+ORDER BY distance_meters;
+
+-- name: CreateSplit :exec
+INSERT INTO splits (result_id, distance_meters, elapsed_ms)
+VALUES (?, ?, ?);
+
+-- name: DeleteResultSplits :exec
+DELETE FROM splits WHERE result_id = ?;
+
+-- name: GetAthleteWorkouts :many
+SELECT id, athlete_id, date, type, distance_meters, duration_ms, effort, notes, created_at
+FROM workouts
+WHERE athlete_id = sqlc.arg(athlete_id) AND date >= sqlc.arg(from_date) AND date <= sqlc.arg(to_date)
+ORDER BY date DESC, id DESC;
+
+-- name: GetWorkout :one
+SELECT id, athlete_id, date, type, distance_meters, duration_ms, effort, notes, created_at
+FROM workouts
+WHERE id = ? AND athlete_id = ?;
+
+-- name: GetWorkoutsBetween :many
+SELECT w.id, w.date, w.distance_meters, w.duration_ms, a.id AS athlete_id, a.name AS athlete_name, a.division AS athlete_division
+FROM workouts w
+JOIN athletes a ON w.athlete_id = a.id
+WHERE w.date >= sqlc.arg(from_date) AND w.date <= sqlc.arg(to_date)
+ORDER BY w.date, w.id;
+
+-- name: CreateWorkout :execresult
+INSERT INTO workouts (athlete_id, date, type, distance_meters, duration_ms, effort, notes)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: UpdateWorkout :exec
+UPDATE workouts
+SET date = ?, type = ?, distance_meters = ?, duration_ms = ?, effort = ?, notes = ?
+WHERE id = ? AND athlete_id = ?;
+
+-- name: DeleteWorkout :exec
+DELETE FROM workouts WHERE id = ? AND athlete_id = ?;
+
+-- name: GetMeetEntries :many
+SELECT e.id, e.race_id, e.bib, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, a.division AS athlete_division
+FROM entries e
+JOIN athletes a ON e.athlete_id = a.id
+JOIN meets m ON e.meet_id = m.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+WHERE e.meet_id = ?
+ORDER BY e.race_id, a.name;
+
+-- name: CountOtherRaceEntries :one
+SELECT COUNT(*)
+FROM entries
+WHERE race_id = ? AND athlete_id <> ?;
+
+-- name: CreateEntry :execresult
+INSERT INTO entries (meet_id, race_id, athlete_id, bib)
+VALUES (?, ?, ?, ?);
+
+-- name: GetEntryByBib :one
+SELECT e.race_id, a.id AS athlete_id, a.name AS athlete_name
+FROM entries e
+JOIN athletes a ON e.athlete_id = a.id
+WHERE e.meet_id = ? AND e.bib = ?;
+
+-- name: DeleteEntry :exec
+DELETE FROM entries WHERE meet_id = ? AND athlete_id = ?;
+
+-- name: DeleteMeetEntries :exec
+DELETE FROM entries WHERE meet_id = ?;
+
+-- name: GetUnenteredResults :many
+SELECT r.id, r.status, r.time_ms, r.place, a.id AS athlete_id, a.name AS athlete_name, a.grade AS athlete_grade, sr.grade AS season_grade, sc.id AS school_id, sc.name AS school_name, ra.id AS race_id, ra.name AS race_name, ra.distance_meters, e.race_id AS entered_race_id
+FROM results r
+JOIN athletes a ON r.athlete_id = a.id
+JOIN schools sc ON a.school_id = sc.id
+JOIN races ra ON r.race_id = ra.id
+JOIN meets m ON r.meet_id = m.id
+LEFT JOIN season_rosters sr ON sr.season_id = m.season_id AND sr.athlete_id = a.id
+LEFT JOIN entries e ON e.meet_id = r.meet_id AND e.athlete_id = r.athlete_id
+WHERE r.meet_id = ? AND sc.is_home AND (e.id IS NULL OR e.race_id <> r.race_id)
+ORDER BY ra.start_time, ra.id, r.place IS NULL, r.place;
+
+-- name: CreateTimingSession :execresult
+INSERT INTO timing_sessions (meet_id, race_id, started_at)
+VALUES (?, ?, ?);
+
+-- name: GetTimingSession :one
+SELECT ts.id, ts.meet_id, ts.race_id, ra.name AS race_name, ts.status, ts.started_at
+FROM timing_sessions ts
+JOIN races ra ON ts.race_id = ra.id
+WHERE ts.id = ?;
+
+-- name: GetMeetTimingSessions :many
+SELECT ts.id, ts.meet_id, ts.race_id, ra.name AS race_name, ts.status, ts.started_at
+FROM timing_sessions ts
+JOIN races ra ON ts.race_id = ra.id
+WHERE ts.meet_id = ?
+ORDER BY ts.started_at, ts.id;
+
+-- name: CountRunningTimingSessions :one
+SELECT COUNT(*)
+FROM timing_sessions
+WHERE race_id = ? AND status = 'running';
+
+-- name: FinalizeTimingSession :execrows
+UPDATE timing_sessions SET status = 'finalized' WHERE id = ? AND status = 'running';
+
+-- name: DeleteTimingSession :exec
+DELETE FROM timing_sessions WHERE id = ?;
+
+-- name: CreateTimingTap :execresult
+INSERT INTO timing_taps (session_id, elapsed_ms, recorded_at)
+VALUES (?, ?, ?);
+
+-- name: GetTimingTaps :many
+SELECT t.id, t.elapsed_ms, t.athlete_id, a.name AS athlete_name, t.recorded_at
+FROM timing_taps t
+LEFT JOIN athletes a ON t.athlete_id = a.id
+WHERE t.session_id = ?
+ORDER BY t.elapsed_ms, t.id;
+
+-- name: UpdateTimingTapTime :exec
+UPDATE timing_taps SET elapsed_ms = ? WHERE id = ? AND session_id = ?;
+
+-- name: AssignTimingTap :exec
+UPDATE timing_taps SET athlete_id = ? WHERE id = ? AND session_id = ?;
+
+-- name: DeleteTimingTap :exec
+DELETE FROM timing_taps WHERE id = ? AND session_id = ?;
+
+-- name: GetSchools :many
+SELECT id, name, is_home, created_at
+FROM schools
+ORDER BY is_home DESC, name;
+
+-- name: GetSchoolByID :one
+SELECT id, name, is_home, created_at
+FROM schools
+WHERE id = ?;
+
+-- name: GetSchoolByName :one
+SELECT id, name, is_home, created_at
+FROM schools
+WHERE name = ?;
+
+-- name: GetHomeSchool :one
+SELECT id, name, is_home, created_at
+FROM schools
+WHERE is_home
+LIMIT 1;
+
+-- name: CreateSchool :execresult
+INSERT INTO schools (name)
+VALUES (?);
+
+-- name: UpdateSchool :exec
+UPDATE schools
+SET name = ?
+WHERE id = ?;
+
+-- name: DeleteSchool :exec
+DELETE FROM schools WHERE id = ?;
+
+-- name: GetSchoolAthletes :many
+SELECT id, name, grade, division, school_id, status, events, created_at
+FROM athletes
+WHERE school_id = ?
+ORDER BY division, name;
+
+-- name: CountSchoolAthletes :one
+SELECT COUNT(*) FROM athletes WHERE school_id = ?;
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// raceStartLayout is the format race start times are sent and received in.
+const raceStartLayout = "2006-01-02T15:04"
+
+type RaceResponse struct {
+	ID             int32  `json:"id"`
+	MeetID         int32  `json:"meetId"`
+	Name           string `json:"name"`
+	DistanceMeters int32  `json:"distanceMeters"`
+	EntryLimit     int32  `json:"entryLimit"`
+	StartTime      string `json:"startTime"`
+}
+
+// CreateRaceRequest describes a race. EntryLimit caps how many athletes can
+// be entered; zero leaves it open.
+type CreateRaceRequest struct {
+	Name           string `json:"name" binding:"required"`
+	DistanceMeters int32  `json:"distanceMeters" binding:"required,gt=0"`
+	EntryLimit     int32  `json:"entryLimit" binding:"omitempty,gt=0"`
+	StartTime      string `json:"startTime"`
+}
+
+type RaceTeamScoresResponse struct {
+	RaceID   int32       `json:"raceId"`
+	RaceName string      `json:"raceName"`
+	Teams    []TeamScore `json:"teams"`
+}
+
+func newRaceResponse(r db.Race) RaceResponse {
+	resp := RaceResponse{
+		ID:             r.ID,
+		MeetID:         r.MeetID,
+		Name:           r.Name,
+		DistanceMeters: r.DistanceMeters,
+		EntryLimit:     r.EntryLimit.Int32,
+	}
+	if r.StartTime.Valid {
+		resp.StartTime = r.StartTime.Time.Format(raceStartLayout)
+	}
+	return resp
+}
+
+// parseRaceStart parses an optional race start time.
+func parseRaceStart(s string) (sql.NullTime, error) {
+	if s == "" {
+		return sql.NullTime{}, nil
+	}
+	t, err := time.Parse(raceStartLayout, s)
+	if err != nil {
+		return sql.NullTime{}, badRequest("Invalid start time format, use YYYY-MM-DDTHH:MM")
+	}
+	return sql.NullTime{Time: t, Valid: true}, nil
+}
+
+// createDefaultRace gives a new meet its first race, run over the meet's
+// course distance or the standard distance when it has no course, so results
+// can be recorded without naming a race. Divisions can be split out later
+// through the races API.
+func createDefaultRace(ctx context.Context, q *db.Queries, meetID int32, courseID sql.NullInt32) error {
+	distance := int32(defaultDistanceMeters)
+	if courseID.Valid {
+		course, err := q.GetCourseByID(ctx, courseID.Int32)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return badRequest("Course not found")
+			}
+			return err
+		}
+		distance = course.DistanceMeters
+	}
+
+	name := fmt.Sprintf("Open %dm", distance)
+	if distance%1000 == 0 {
+		name = fmt.Sprintf("Open %dK", distance/1000)
+	}
+	_, err := q.CreateRace(ctx, db.CreateRaceParams{
+		MeetID:         meetID,
+		Name:           name,
+		DistanceMeters: distance,
+	})
+	return err
+}
+
+// resolveRace returns the race a result belongs to. The race may be omitted
+// when the meet has exactly one; otherwise it must belong to the given meet.
+func resolveRace(ctx context.Context, q *db.Queries, meetID, raceID int32) (db.Race, error) {
+	if raceID == 0 {
+		races, err := q.GetRacesByMeetID(ctx, meetID)
+		if err != nil {
+			return db.Race{}, err
+		}
+		if len(races) != 1 {
+			return db.Race{}, badRequest("raceId is required unless the meet has exactly one race")
+		}
+		return races[0], nil
+	}
+
+	race, err := q.GetRaceByID(ctx, raceID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Race{}, badRequest("Race not found")
+		}
+		return db.Race{}, err
+	}
+	if race.MeetID != meetID {
+		return db.Race{}, badRequest("Race does not belong to this meet")
+	}
+	return race, nil
+}
+
+func registerRaceRoutes(r *gin.Engine) {
+	// Get all races in a meet
+	r.GET("/api/meets/:id/races", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meet ID"})
+			return
+		}
+
+		races, err := queries.GetRacesByMeetID(context.Background(), int32(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]RaceResponse, len(races))
+		for i, race := range races {
+			response[i] = newRaceResponse(race)
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Create a race in a meet
+	r.POST("/api/meets/:id/races", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meet ID"})
+			return
+		}
+
+		var req CreateRaceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		startTime, err := parseRaceStart(req.StartTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if _, err := queries.GetMeetByID(context.Background(), int32(id)); err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Meet not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := queries.CreateRace(context.Background(), db.CreateRaceParams{
+			MeetID:         int32(id),
+			Name:           req.Name,
+			DistanceMeters: req.DistanceMeters,
+			EntryLimit:     nullEntryLimit(req.EntryLimit),
+			StartTime:      startTime,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		raceID, _ := result.LastInsertId()
+		c.JSON(http.StatusCreated, gin.H{"id": raceID, "message": "Race created successfully"})
+	})
+
+	// Get race by ID
+	r.GET("/api/races/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid race ID"})
+			return
+		}
+
+		race, err := queries.GetRaceByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Race not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, newRaceResponse(race))
+	})
+
+	// Update a race
+	r.PUT("/api/races/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid race ID"})
+			return
+		}
+
+		var req CreateRaceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		startTime, err := parseRaceStart(req.StartTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := checkEntryLimit(context.Background(), int32(id), req.EntryLimit); err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		err = queries.UpdateRace(context.Background(), db.UpdateRaceParams{
+			ID:             int32(id),
+			Name:           req.Name,
+			DistanceMeters: req.DistanceMeters,
+			EntryLimit:     nullEntryLimit(req.EntryLimit),
+			StartTime:      startTime,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Race updated successfully"})
+	})
+
+	// Delete a race and its results
+	r.DELETE("/api/races/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid race ID"})
+			return
+		}
+
+		err = queries.DeleteRace(context.Background(), int32(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Race deleted successfully"})
+	})
+
+	// Get results for a race
+	r.GET("/api/races/:id/results", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid race ID"})
+			return
+		}
+
+		race, err := queries.GetRaceByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Race not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		results, err := queries.GetRaceResults(context.Background(), race.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]MeetResultResponse, len(results))
+		for i, r := range results {
+			response[i] = MeetResultResponse{
+				ID:           r.ID,
+				Status:       r.Status,
+				Time:         RaceTime(r.TimeMs.Int32),
+				Place:        r.Place.Int32,
+				AthleteID:    r.AthleteID,
+				AthleteName:  r.AthleteName,
+				AthleteGrade: gradeAt(r.AthleteGrade, r.SeasonGrade),
+				SchoolID:     r.SchoolID,
+				School:       r.SchoolName,
+				RaceID:       race.ID,
+				RaceName:     race.Name,
+				Pacing:       newPacing(RaceTime(r.TimeMs.Int32), race.DistanceMeters),
+			}
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Get team scores for a race
+	r.GET("/api/races/:id/team-scores", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid race ID"})
+			return
+		}
+
+		results, err := queries.GetRaceResults(context.Background(), int32(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		var finishers []Finisher
+		for _, r := range results {
+			if r.Status != statusFinished {
+				continue
+			}
+			finishers = append(finishers, Finisher{
+				ResultID:  r.ID,
+				AthleteID: r.AthleteID,
+				Name:      r.AthleteName,
+				Team:      r.SchoolName,
+				Place:     r.Place.Int32,
+				Time:      RaceTime(r.TimeMs.Int32),
+			})
+		}
+		c.JSON(http.StatusOK, ScoreTeams(finishers))
+	})
+}
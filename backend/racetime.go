@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RaceTime is an elapsed race time with millisecond precision. It is stored
+// in the database as an integer number of milliseconds and serialized to JSON
+// in the familiar clock form ("16:45", "16:45.3", "1:02:10").
+type RaceTime int32
+
+// ParseRaceTime parses a time written as SS, M:SS or H:MM:SS, each optionally
+// followed by up to three fractional digits (e.g. "16:45.3").
+func ParseRaceTime(s string) (RaceTime, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("invalid race time %q: empty", s)
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid race time %q: too many fields", s)
+	}
+
+	// The last field holds seconds and the optional fraction.
+	secField := parts[len(parts)-1]
+	fracMs := 0
+	if dot := strings.IndexByte(secField, '.'); dot >= 0 {
+		frac := secField[dot+1:]
+		if frac == "" || len(frac) > 3 || !isDigits(frac) {
+			return 0, fmt.Errorf("invalid race time %q: bad fractional seconds", s)
+		}
+		fracMs, _ = strconv.Atoi(frac + strings.Repeat("0", 3-len(frac)))
+		secField = secField[:dot]
+	}
+
+	fields := append(parts[:len(parts)-1:len(parts)-1], secField)
+	values := make([]int, len(fields))
+	for i, f := range fields {
+		if f == "" || !isDigits(f) {
+			return 0, fmt.Errorf("invalid race time %q: expected digits", s)
+		}
+		values[i], _ = strconv.Atoi(f)
+		// Every field after the leading one is a base-60 component.
+		if i > 0 && (len(f) != 2 || values[i] >= 60) {
+			return 0, fmt.Errorf("invalid race time %q: minutes and seconds must be two digits below 60", s)
+		}
+	}
+
+	total := 0
+	for _, v := range values {
+		total = total*60 + v
+	}
+	ms := int64(total)*1000 + int64(fracMs)
+	if ms <= 0 {
+		return 0, fmt.Errorf("invalid race time %q: must be greater than zero", s)
+	}
+	if ms > maxRaceTimeMs {
+		return 0, fmt.Errorf("invalid race time %q: too long", s)
+	}
+	return RaceTime(ms), nil
+}
+
+// maxRaceTimeMs caps parsed times well below the int32 column limit.
+const maxRaceTimeMs = 24 * 60 * 60 * 1000
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Milliseconds returns the time as stored in the database.
+func (t RaceTime) Milliseconds() int32 {
+	return int32(t)
+}
+
+// Seconds returns the time in fractional seconds.
+func (t RaceTime) Seconds() float64 {
+	return float64(t) / 1000
+}
+
+// Units that paces are given in.
+const (
+	metersPerMile = 1609.344
+	metersPerKm   = 1000
+)
+
+// Pace returns the average time taken per unitMeters over a race of
+// distanceMeters, rounded to the nearest second.
+func (t RaceTime) Pace(distanceMeters int32, unitMeters float64) RaceTime {
+	if distanceMeters <= 0 {
+		return 0
+	}
+	perUnit := float64(t) * unitMeters / float64(distanceMeters)
+	return RaceTime(math.Round(perUnit/1000) * 1000)
+}
+
+// riegelExponent is the fatigue factor in Riegel's formula, which scales a
+// time at one distance to an equivalent effort at another.
+const riegelExponent = 1.06
+
+// Equivalent returns the time a runner who ran t over fromMeters would be
+// expected to run over toMeters, rounded to the nearest second.
+func (t RaceTime) Equivalent(fromMeters, toMeters int32) RaceTime {
+	if fromMeters <= 0 || toMeters <= 0 {
+		return 0
+	}
+	scaled := float64(t) * math.Pow(float64(toMeters)/float64(fromMeters), riegelExponent)
+	return RaceTime(math.Round(scaled/1000) * 1000)
+}
+
+// String formats the time as M:SS or H:MM:SS, adding only as many fractional
+// digits as are needed. The zero value formats as an empty string.
+func (t RaceTime) String() string {
+	if t <= 0 {
+		return ""
+	}
+	ms := int(t)
+	hours := ms / 3600000
+	minutes := ms / 60000 % 60
+	seconds := ms / 1000 % 60
+	frac := ms % 1000
+
+	var s string
+	if hours > 0 {
+		s = fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	} else {
+		s = fmt.Sprintf("%d:%02d", minutes, seconds)
+	}
+	if frac > 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%03d", frac), "0")
+	}
+	return s
+}
+
+// MarshalJSON encodes the time as its string form.
+func (t RaceTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON accepts the string form. An empty string decodes to the zero
+// value so optional fields can be left blank.
+func (t *RaceTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("race time must be a string like \"16:45\"")
+	}
+	if strings.TrimSpace(s) == "" {
+		*t = 0
+		return nil
+	}
+	parsed, err := ParseRaceTime(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
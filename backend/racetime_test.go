@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseRaceTime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want RaceTime
+	}{
+		{"45", 45000},
+		{"59.9", 59900},
+		{"16:45", 1005000},
+		{"16:45.3", 1005300},
+		{"16:45.32", 1005320},
+		{"16:45.321", 1005321},
+		{" 16:45 ", 1005000},
+		{"0:01", 1000},
+		{"90:00", 5400000},
+		{"1:02:10", 3730000},
+		{"1:02:10.5", 3730500},
+		{"24:00:00", 86400000},
+	}
+	for _, tt := range tests {
+		got, err := ParseRaceTime(tt.in)
+		if err != nil {
+			t.Errorf("ParseRaceTime(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRaceTime(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseRaceTimeErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"   ",
+		"1:2:3:4",
+		"16:45.",
+		"16:45.1234",
+		"16:45.3a",
+		"16:5",
+		"16:60",
+		"1:60:00",
+		"1:5:00",
+		"16:",
+		":45",
+		"-16:45",
+		"16m45",
+		"0",
+		"0:00.000",
+		"24:00:00.001",
+	} {
+		if got, err := ParseRaceTime(in); err == nil {
+			t.Errorf("ParseRaceTime(%q) = %d, want error", in, got)
+		}
+	}
+}
+
+func TestRaceTimeString(t *testing.T) {
+	tests := []struct {
+		in   RaceTime
+		want string
+	}{
+		{0, ""},
+		{-1000, ""},
+		{1, "0:00.001"},
+		{45000, "0:45"},
+		{1005000, "16:45"},
+		{1005300, "16:45.3"},
+		{1005320, "16:45.32"},
+		{1005321, "16:45.321"},
+		{3599000, "59:59"},
+		{3600000, "1:00:00"},
+		{3730500, "1:02:10.5"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("RaceTime(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRaceTimeRoundTrip(t *testing.T) {
+	for _, s := range []string{"0:45", "16:45", "16:45.3", "16:45.321", "1:02:10.5"} {
+		parsed, err := ParseRaceTime(s)
+		if err != nil {
+			t.Fatalf("ParseRaceTime(%q) error: %v", s, err)
+		}
+		if got := parsed.String(); got != s {
+			t.Errorf("ParseRaceTime(%q).String() = %q", s, got)
+		}
+	}
+}
+
+func TestRaceTimeJSON(t *testing.T) {
+	var v struct {
+		Time RaceTime `json:"time"`
+	}
+	if err := json.Unmarshal([]byte(`{"time":"16:45.3"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Time != 1005300 {
+		t.Errorf("decoded %d, want 1005300", v.Time)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"time":"16:45.3"}` {
+		t.Errorf("encoded %s", out)
+	}
+
+	if err := json.Unmarshal([]byte(`{"time":""}`), &v); err != nil || v.Time != 0 {
+		t.Errorf("blank time decoded to %d, %v", v.Time, err)
+	}
+	for _, in := range []string{`{"time":1005}`, `{"time":"16:61"}`} {
+		if err := json.Unmarshal([]byte(in), &v); err == nil {
+			t.Errorf("json.Unmarshal(%s) succeeded, want error", in)
+		}
+	}
+}
+
+func TestRaceTimePace(t *testing.T) {
+	tests := []struct {
+		time     RaceTime
+		distance int32
+		unit     float64
+		want     RaceTime
+	}{
+		{1005000, 5000, metersPerKm, 201000},
+		{1005000, 5000, metersPerMile, 323000},
+		{300000, 1609, metersPerMile, 300000},
+		{1005000, 0, metersPerMile, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.time.Pace(tt.distance, tt.unit); got != tt.want {
+			t.Errorf("RaceTime(%d).Pace(%d, %v) = %s, want %s", tt.time, tt.distance, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestRaceTimeEquivalent(t *testing.T) {
+	tests := []struct {
+		time     RaceTime
+		from, to int32
+		want     RaceTime
+	}{
+		{1005000, 5000, 5000, 1005000},
+		{1005000, 5000, 0, 0},
+		{1005000, 0, 5000, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.time.Equivalent(tt.from, tt.to); got != tt.want {
+			t.Errorf("RaceTime(%d).Equivalent(%d, %d) = %s, want %s", tt.time, tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	// A longer race is slower, and more than in proportion to the distance.
+	t5k := RaceTime(1005000)
+	t8k := t5k.Equivalent(5000, 8000)
+	if t8k <= t5k*8/5 {
+		t.Errorf("8K equivalent of %s = %s, want slower than %s", t5k, t8k, t5k*8/5)
+	}
+	if back := t8k.Equivalent(8000, 5000); back < t5k-1000 || back > t5k+1000 {
+		t.Errorf("converting %s back to 5K gave %s", t8k, back)
+	}
+}
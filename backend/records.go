@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"jones-county-xc/backend/db"
+)
+
+// PersonalRecord is an athlete's best mark at one race distance.
+type PersonalRecord struct {
+	DistanceMeters int32    `json:"distanceMeters"`
+	Time           RaceTime `json:"time"`
+	ResultID       int32    `json:"resultId"`
+	MeetID         int32    `json:"meetId"`
+	MeetName       string   `json:"meetName"`
+	MeetDate       string   `json:"meetDate"`
+}
+
+// mark is a single result reduced to what record keeping needs.
+type mark struct {
+	ResultID  int32
+	AthleteID int32
+	Distance  int32
+	Time      RaceTime
+	MeetID    int32
+	MeetName  string
+	MeetDate  time.Time
+	SeasonID  sql.NullInt32
+}
+
+func marksFromRows(rows []db.GetResultMarksForAthletesRow) []mark {
+	marks := make([]mark, len(rows))
+	for i, r := range rows {
+		marks[i] = mark{
+			ResultID:  r.ID,
+			AthleteID: r.AthleteID,
+			Distance:  r.DistanceMeters,
+			Time:      RaceTime(r.TimeMs.Int32),
+			MeetID:    r.MeetID,
+			MeetName:  r.MeetName,
+			MeetDate:  r.MeetDate,
+			SeasonID:  r.SeasonID,
+		}
+	}
+	return marks
+}
+
+func marksFromAthleteRows(rows []db.GetAthleteResultMarksRow) []mark {
+	marks := make([]mark, len(rows))
+	for i, r := range rows {
+		marks[i] = mark{
+			ResultID:  r.ID,
+			AthleteID: r.AthleteID,
+			Distance:  r.DistanceMeters,
+			Time:      RaceTime(r.TimeMs.Int32),
+			MeetID:    r.MeetID,
+			MeetName:  r.MeetName,
+			MeetDate:  r.MeetDate,
+			SeasonID:  r.SeasonID,
+		}
+	}
+	return marks
+}
+
+// personalRecords returns the best mark at each distance, ordered by distance.
+// Marks must be in date order so a tied time credits the meet where it was
+// first run.
+func personalRecords(marks []mark) []PersonalRecord {
+	best := make(map[int32]mark)
+	for _, m := range marks {
+		if b, ok := best[m.Distance]; !ok || m.Time < b.Time {
+			best[m.Distance] = m
+		}
+	}
+
+	records := make([]PersonalRecord, 0, len(best))
+	for _, m := range best {
+		records = append(records, PersonalRecord{
+			DistanceMeters: m.Distance,
+			Time:           m.Time,
+			ResultID:       m.ResultID,
+			MeetID:         m.MeetID,
+			MeetName:       m.MeetName,
+			MeetDate:       m.MeetDate.Format("2006-01-02"),
+		})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].DistanceMeters < records[j].DistanceMeters
+	})
+	return records
+}
+
+// personalRecordsByAthlete groups marks by athlete and computes each
+// athlete's records.
+func personalRecordsByAthlete(marks []mark) map[int32][]PersonalRecord {
+	byAthlete := make(map[int32][]mark)
+	for _, m := range marks {
+		byAthlete[m.AthleteID] = append(byAthlete[m.AthleteID], m)
+	}
+	records := make(map[int32][]PersonalRecord, len(byAthlete))
+	for id, ms := range byAthlete {
+		records[id] = personalRecords(ms)
+	}
+	return records
+}
+
+// athletesPersonalRecords loads the records of a list of athletes, such as
+// one page of the roster, reading only their own results.
+func athletesPersonalRecords(ctx context.Context, athletes []db.Athlete) (map[int32][]PersonalRecord, error) {
+	ids := make([]int32, len(athletes))
+	for i, a := range athletes {
+		ids[i] = a.ID
+	}
+	rows, err := queries.GetResultMarksForAthletes(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return personalRecordsByAthlete(marksFromRows(rows)), nil
+}
+
+// recordFor returns the record at a distance, or the zero time if the athlete
+// has not run it.
+func recordFor(records []PersonalRecord, distance int32) RaceTime {
+	for _, r := range records {
+		if r.DistanceMeters == distance {
+			return r.Time
+		}
+	}
+	return 0
+}
+
+// recordFlags reports whether m was a personal record and a season best when
+// it was run, compared with the athlete's other marks at the same distance up
+// to that date.
+func recordFlags(marks []mark, m mark) (personalRecord, seasonBest bool) {
+	personalRecord, seasonBest = true, true
+	for _, other := range marks {
+		if other.ResultID == m.ResultID || other.Distance != m.Distance || other.MeetDate.After(m.MeetDate) {
+			continue
+		}
+		if other.Time <= m.Time {
+			personalRecord = false
+			if sameSeason(other, m) {
+				seasonBest = false
+			}
+		}
+	}
+	return personalRecord, seasonBest
+}
+
+// sameSeason reports whether two marks were run in the same season. Meets
+// not assigned to a season fall back to the calendar year.
+func sameSeason(a, b mark) bool {
+	if a.SeasonID.Valid && b.SeasonID.Valid {
+		return a.SeasonID.Int32 == b.SeasonID.Int32
+	}
+	return a.MeetDate.Year() == b.MeetDate.Year()
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Result statuses. Only finished results have a time and place; the others
+// record athletes who did not finish, did not start or were disqualified, and
+// are left out of rankings, team scores and records.
+const (
+	statusFinished = "finished"
+	statusDNF      = "dnf"
+	statusDNS      = "dns"
+	statusDQ       = "dq"
+)
+
+// CreatedResult describes a result that was just recorded.
+type CreatedResult struct {
+	ID             int64 `json:"id"`
+	PersonalRecord bool  `json:"personalRecord"`
+	SeasonBest     bool  `json:"seasonBest"`
+	Pacing
+}
+
+type CreateResultResponse struct {
+	Message string `json:"message"`
+	CreatedResult
+}
+
+type BulkResultRow struct {
+	AthleteID int32  `json:"athleteId"`
+	RaceID    int32  `json:"raceId"`
+	Status    string `json:"status"`
+	Time      string `json:"time"`
+	Place     int32  `json:"place"`
+}
+
+type BulkResultsRequest struct {
+	RaceID  int32           `json:"raceId"`
+	Results []BulkResultRow `json:"results" binding:"required,min=1"`
+}
+
+// RowError reports why one row of a batch was rejected. Rows are numbered
+// from 1.
+type RowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+type BulkResultResponse struct {
+	Row int `json:"row"`
+	CreatedResult
+}
+
+// validateResultStatus defaults a request's status to finished and checks
+// that its time and place agree with the status.
+func validateResultStatus(req *CreateResultRequest) error {
+	if req.Status == "" {
+		req.Status = statusFinished
+	}
+	switch req.Status {
+	case statusFinished:
+		if req.Time <= 0 {
+			return badRequest("A finished result needs a time")
+		}
+		if req.Place <= 0 {
+			return badRequest("A finished result needs a place")
+		}
+	case statusDNF, statusDNS, statusDQ:
+		if req.Time != 0 || req.Place != 0 {
+			return badRequest("Only finished results have a time and place")
+		}
+	default:
+		return badRequest("Invalid status, use finished, dnf, dns or dq")
+	}
+	return nil
+}
+
+// nullResultValue stores a time or place, which non-finishers leave at zero.
+func nullResultValue(v int32) sql.NullInt32 {
+	return sql.NullInt32{Int32: v, Valid: v > 0}
+}
+
+// createResult validates and records a single result, then flags it as a
+// personal record or season best. Every path that adds results goes through
+// here so they are checked the same way.
+func createResult(ctx context.Context, q *db.Queries, req CreateResultRequest) (CreatedResult, error) {
+	if err := validateResultStatus(&req); err != nil {
+		return CreatedResult{}, err
+	}
+	if _, err := q.GetAthleteByID(ctx, req.AthleteID); err != nil {
+		if err == sql.ErrNoRows {
+			return CreatedResult{}, badRequest("Athlete not found")
+		}
+		return CreatedResult{}, err
+	}
+
+	race, err := resolveRace(ctx, q, req.MeetID, req.RaceID)
+	if err != nil {
+		return CreatedResult{}, err
+	}
+
+	result, err := q.CreateResult(ctx, db.CreateResultParams{
+		AthleteID: req.AthleteID,
+		MeetID:    req.MeetID,
+		RaceID:    race.ID,
+		Status:    req.Status,
+		TimeMs:    nullResultValue(req.Time.Milliseconds()),
+		Place:     nullResultValue(req.Place),
+	})
+	if err != nil {
+		// The athlete may have had a result saved since it was checked.
+		if isDuplicateKey(err) {
+			return CreatedResult{}, badRequest(fmt.Sprintf("Athlete %d already has a result in %s", req.AthleteID, race.Name))
+		}
+		return CreatedResult{}, err
+	}
+
+	created := CreatedResult{Pacing: newPacing(req.Time, race.DistanceMeters)}
+	created.ID, _ = result.LastInsertId()
+
+	// Flag the new result against the athlete's other marks.
+	rows, err := q.GetAthleteResultMarks(ctx, req.AthleteID)
+	if err != nil {
+		return CreatedResult{}, err
+	}
+	marks := marksFromAthleteRows(rows)
+	for _, m := range marks {
+		if m.ResultID == int32(created.ID) {
+			created.PersonalRecord, created.SeasonBest = recordFlags(marks, m)
+		}
+	}
+	return created, nil
+}
+
+// validateBulkResults checks a whole batch for a meet before anything is
+// written: every athlete must exist, times and places must suit the status,
+// and within each race no athlete or place may repeat, including against
+// results already saved.
+// It returns the rows as result requests along with any per-row errors.
+func validateBulkResults(ctx context.Context, q *db.Queries, meetID int32, req BulkResultsRequest) ([]CreateResultRequest, []RowError, error) {
+	athletes, err := q.GetAllAthletes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	known := make(map[int32]bool, len(athletes))
+	for _, a := range athletes {
+		known[a.ID] = true
+	}
+
+	type raceState struct {
+		athletes map[int32]bool
+		places   map[int32]bool
+	}
+	races := make(map[int32]*raceState)
+
+	requests := make([]CreateResultRequest, len(req.Results))
+	var rowErrors []RowError
+	for i, row := range req.Results {
+		fail := func(format string, args ...any) {
+			rowErrors = append(rowErrors, RowError{Row: i + 1, Error: fmt.Sprintf(format, args...)})
+		}
+
+		if !known[row.AthleteID] {
+			fail("Athlete %d not found", row.AthleteID)
+			continue
+		}
+		result := CreateResultRequest{
+			AthleteID: row.AthleteID,
+			MeetID:    meetID,
+			Status:    row.Status,
+			Place:     row.Place,
+		}
+		if row.Time != "" {
+			t, err := ParseRaceTime(row.Time)
+			if err != nil {
+				fail("%s", err.Error())
+				continue
+			}
+			result.Time = t
+		}
+		if err := validateResultStatus(&result); err != nil {
+			fail("%s", err.Error())
+			continue
+		}
+
+		raceID := row.RaceID
+		if raceID == 0 {
+			raceID = req.RaceID
+		}
+		race, err := resolveRace(ctx, q, meetID, raceID)
+		if err != nil {
+			if errorStatus(err) != http.StatusBadRequest {
+				return nil, nil, err
+			}
+			fail("%s", err.Error())
+			continue
+		}
+
+		state, ok := races[race.ID]
+		if !ok {
+			existing, err := q.GetRaceResults(ctx, race.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			state = &raceState{athletes: make(map[int32]bool), places: make(map[int32]bool)}
+			for _, r := range existing {
+				state.athletes[r.AthleteID] = true
+				if r.Place.Valid {
+					state.places[r.Place.Int32] = true
+				}
+			}
+			races[race.ID] = state
+		}
+		if state.athletes[row.AthleteID] {
+			fail("Athlete %d already has a result in %s", row.AthleteID, race.Name)
+			continue
+		}
+		if result.Place > 0 && state.places[result.Place] {
+			fail("Place %d is already taken in %s", result.Place, race.Name)
+			continue
+		}
+		state.athletes[row.AthleteID] = true
+		if result.Place > 0 {
+			state.places[result.Place] = true
+		}
+
+		result.RaceID = race.ID
+		requests[i] = result
+	}
+	return requests, rowErrors, nil
+}
+
+// createResults records a validated batch in a single transaction, then
+// announces the new results once they are committed.
+func createResults(ctx context.Context, requests []CreateResultRequest) ([]CreatedResult, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	created, err := insertResults(ctx, queries.WithTx(tx), requests)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	publishCreated(ctx, created)
+	return created, nil
+}
+
+// insertResults records a batch on q, which is expected to be part of a
+// transaction the caller commits.
+func insertResults(ctx context.Context, q *db.Queries, requests []CreateResultRequest) ([]CreatedResult, error) {
+	created := make([]CreatedResult, len(requests))
+	for i, req := range requests {
+		var err error
+		if created[i], err = createResult(ctx, q, req); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+	}
+	return created, nil
+}
+
+// publishCreated announces a committed batch on the live stream.
+func publishCreated(ctx context.Context, created []CreatedResult) {
+	for _, cr := range created {
+		publishResult(ctx, liveResultCreated, int32(cr.ID))
+	}
+}
+
+func registerResultRoutes(r *gin.Engine) {
+	// Create all of a meet's results at once; nothing is saved unless every
+	// row is valid
+	r.POST("/api/meets/:id/results/bulk", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meet ID"})
+			return
+		}
+
+		var req BulkResultsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if _, err := queries.GetMeetByID(context.Background(), int32(id)); err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Meet not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		requests, rowErrors, err := validateBulkResults(context.Background(), queries, int32(id), req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(rowErrors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No results were saved", "rows": rowErrors})
+			return
+		}
+
+		created, err := createResults(context.Background(), requests)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]BulkResultResponse, len(created))
+		for i, cr := range created {
+			response[i] = BulkResultResponse{Row: i + 1, CreatedResult: cr}
+		}
+		c.JSON(http.StatusCreated, gin.H{
+			"message": fmt.Sprintf("%d results created successfully", len(created)),
+			"results": response,
+		})
+	})
+}
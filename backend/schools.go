@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SchoolResponse struct {
+	ID     int32  `json:"id"`
+	Name   string `json:"name"`
+	IsHome bool   `json:"isHome"`
+}
+
+type CreateSchoolRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type SchoolRosterResponse struct {
+	School   SchoolResponse    `json:"school"`
+	Athletes []AthleteResponse `json:"athletes"`
+}
+
+func newSchoolResponse(s db.School) SchoolResponse {
+	return SchoolResponse{
+		ID:     s.ID,
+		Name:   s.Name,
+		IsHome: s.IsHome,
+	}
+}
+
+// schoolQuery reads ?schoolId, defaulting to the home school so team views
+// show our own athletes unless another school is asked for.
+func schoolQuery(ctx context.Context, c *gin.Context) (int32, error) {
+	if value := c.Query("schoolId"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, badRequest("Invalid school ID")
+		}
+		return int32(id), nil
+	}
+	home, err := queries.GetHomeSchool(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return home.ID, nil
+}
+
+// athleteSchool resolves the school an athlete is created in or moved to; no
+// school means the home school.
+func athleteSchool(ctx context.Context, schoolID int32) (int32, error) {
+	if schoolID == 0 {
+		home, err := queries.GetHomeSchool(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return home.ID, nil
+	}
+	if _, err := queries.GetSchoolByID(ctx, schoolID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, badRequest("School not found")
+		}
+		return 0, err
+	}
+	return schoolID, nil
+}
+
+// homeAthlete loads an athlete who must run for the home school, such as one
+// being added to a season roster.
+func homeAthlete(ctx context.Context, q *db.Queries, athleteID int32) (db.Athlete, error) {
+	athlete, err := q.GetAthleteByID(ctx, athleteID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Athlete{}, badRequest(fmt.Sprintf("Athlete %d not found", athleteID))
+		}
+		return db.Athlete{}, err
+	}
+	home, err := q.GetHomeSchool(ctx)
+	if err != nil {
+		return db.Athlete{}, err
+	}
+	if athlete.SchoolID != home.ID {
+		return db.Athlete{}, badRequest(fmt.Sprintf("%s does not run for %s", athlete.Name, home.Name))
+	}
+	return athlete, nil
+}
+
+func registerSchoolRoutes(r *gin.Engine) {
+	// Get all schools, home school first
+	r.GET("/api/schools", func(c *gin.Context) {
+		schools, err := queries.GetSchools(context.Background())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]SchoolResponse, len(schools))
+		for i, s := range schools {
+			response[i] = newSchoolResponse(s)
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Get school by ID
+	r.GET("/api/schools/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+			return
+		}
+
+		school, err := queries.GetSchoolByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "School not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, newSchoolResponse(school))
+	})
+
+	// Get a school's athletes with their personal records, for scouting
+	// opponents as well as listing our own roster
+	r.GET("/api/schools/:id/roster", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+			return
+		}
+
+		school, err := queries.GetSchoolByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "School not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		athletes, err := queries.GetSchoolAthletes(context.Background(), school.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		records, err := athletesPersonalRecords(context.Background(), athletes)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := SchoolRosterResponse{
+			School:   newSchoolResponse(school),
+			Athletes: make([]AthleteResponse, len(athletes)),
+		}
+		for i, a := range athletes {
+			response.Athletes[i] = newAthleteResponse(a, records[a.ID])
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Create a school
+	r.POST("/api/schools", func(c *gin.Context) {
+		var req CreateSchoolRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if _, err := queries.GetSchoolByName(context.Background(), req.Name); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "A school with that name already exists"})
+			return
+		} else if err != sql.ErrNoRows {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := queries.CreateSchool(context.Background(), req.Name)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		id, _ := result.LastInsertId()
+		c.JSON(http.StatusCreated, gin.H{"id": id, "message": "School created successfully"})
+	})
+
+	// Rename a school
+	r.PUT("/api/schools/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+			return
+		}
+
+		var req CreateSchoolRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if existing, err := queries.GetSchoolByName(context.Background(), req.Name); err == nil && existing.ID != int32(id) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A school with that name already exists"})
+			return
+		} else if err != nil && err != sql.ErrNoRows {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = queries.UpdateSchool(context.Background(), db.UpdateSchoolParams{
+			ID:   int32(id),
+			Name: req.Name,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "School updated successfully"})
+	})
+
+	// Delete a school. Only schools without athletes can be deleted, and the
+	// home school never can.
+	r.DELETE("/api/schools/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+			return
+		}
+
+		school, err := queries.GetSchoolByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "School not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if school.IsHome {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The home school cannot be deleted"})
+			return
+		}
+		athletes, err := queries.CountSchoolAthletes(context.Background(), school.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if athletes > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "School still has athletes"})
+			return
+		}
+
+		if err := queries.DeleteSchool(context.Background(), school.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "School deleted successfully"})
+	})
+}
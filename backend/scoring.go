@@ -0,0 +1,134 @@
+package main
+
+import "sort"
+
+// Standard cross-country scoring: the top five runners on a team score, the
+// sixth and seventh displace runners on other teams, and a team needs at
+// least five finishers to receive a score.
+const (
+	teamScorers    = 5
+	teamDisplacers = 7
+)
+
+// Finisher is one runner's finish as fed to the team scorer.
+type Finisher struct {
+	ResultID  int32
+	AthleteID int32
+	Name      string
+	Team      string
+	Place     int32
+	Time      RaceTime
+}
+
+// ScoredRunner is a finisher annotated with their team-score place. TeamPlace
+// is zero for runners who neither score nor displace.
+type ScoredRunner struct {
+	ResultID  int32    `json:"resultId"`
+	AthleteID int32    `json:"athleteId"`
+	Name      string   `json:"name"`
+	Place     int32    `json:"place"`
+	Time      RaceTime `json:"time"`
+	TeamPlace int32    `json:"teamPlace"`
+	Scorer    bool     `json:"scorer"`
+}
+
+// TeamScore is one team's result. Non-scoring teams (fewer than five
+// finishers) have Scoring set to false and a zero Place and Score.
+type TeamScore struct {
+	Team        string         `json:"team"`
+	Place       int32          `json:"place"`
+	Score       int32          `json:"score"`
+	Scoring     bool           `json:"scoring"`
+	SixthRunner int32          `json:"sixthRunnerPlace"`
+	Finishers   int            `json:"finishers"`
+	Runners     []ScoredRunner `json:"runners"`
+}
+
+// ScoreTeams applies standard cross-country team scoring to a race's
+// finishers. Teams are returned in finishing order followed by non-scoring
+// teams in alphabetical order.
+func ScoreTeams(finishers []Finisher) []TeamScore {
+	sorted := make([]Finisher, len(finishers))
+	copy(sorted, finishers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Place != sorted[j].Place {
+			return sorted[i].Place < sorted[j].Place
+		}
+		return sorted[i].Time < sorted[j].Time
+	})
+
+	counts := make(map[string]int)
+	for _, f := range sorted {
+		counts[f.Team]++
+	}
+
+	teams := make(map[string]*TeamScore)
+	var order []string
+	var nextPlace int32 = 1
+	for _, f := range sorted {
+		t, ok := teams[f.Team]
+		if !ok {
+			t = &TeamScore{Team: f.Team, Scoring: counts[f.Team] >= teamScorers}
+			teams[f.Team] = t
+			order = append(order, f.Team)
+		}
+
+		runner := ScoredRunner{
+			ResultID:  f.ResultID,
+			AthleteID: f.AthleteID,
+			Name:      f.Name,
+			Place:     f.Place,
+			Time:      f.Time,
+		}
+		// Only the first seven runners from a scoring team take team places;
+		// everyone else is removed from the team-score order.
+		if t.Scoring && len(t.Runners) < teamDisplacers {
+			runner.TeamPlace = nextPlace
+			nextPlace++
+			if len(t.Runners) < teamScorers {
+				runner.Scorer = true
+				t.Score += runner.TeamPlace
+			} else if len(t.Runners) == teamScorers {
+				t.SixthRunner = runner.TeamPlace
+			}
+		}
+		t.Runners = append(t.Runners, runner)
+		t.Finishers++
+	}
+
+	var scoring, nonScoring []TeamScore
+	for _, name := range order {
+		t := teams[name]
+		if t.Scoring {
+			scoring = append(scoring, *t)
+		} else {
+			t.Score = 0
+			nonScoring = append(nonScoring, *t)
+		}
+	}
+
+	sort.SliceStable(scoring, func(i, j int) bool {
+		a, b := scoring[i], scoring[j]
+		if a.Score != b.Score {
+			return a.Score < b.Score
+		}
+		// Ties are broken by the sixth runner; a team without one loses.
+		if (a.SixthRunner == 0) != (b.SixthRunner == 0) {
+			return a.SixthRunner != 0
+		}
+		if a.SixthRunner != b.SixthRunner {
+			return a.SixthRunner < b.SixthRunner
+		}
+		return a.Team < b.Team
+	})
+	for i := range scoring {
+		scoring[i].Place = int32(i + 1)
+	}
+	sort.SliceStable(nonScoring, func(i, j int) bool {
+		return nonScoring[i].Team < nonScoring[j].Team
+	})
+
+	result := make([]TeamScore, 0, len(order))
+	result = append(result, scoring...)
+	return append(result, nonScoring...)
+}
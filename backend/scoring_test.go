@@ -0,0 +1,177 @@
+package main
+
+import "testing"
+
+// finishOrder builds a race's finishers from their teams in finishing order,
+// one letter per runner: "ABA" is A first, then B, then A again.
+func finishOrder(order string) []Finisher {
+	finishers := make([]Finisher, len(order))
+	for i, team := range order {
+		finishers[i] = Finisher{
+			ResultID:  int32(i + 1),
+			AthleteID: int32(i + 1),
+			Team:      string(team),
+			Place:     int32(i + 1),
+			Time:      RaceTime(900000 + i*1000),
+		}
+	}
+	return finishers
+}
+
+// teamPlaces lists the team places a team's runners took, in finishing order.
+func teamPlaces(t TeamScore) []int32 {
+	places := make([]int32, len(t.Runners))
+	for i, r := range t.Runners {
+		places[i] = r.TeamPlace
+	}
+	return places
+}
+
+func TestScoreTeams(t *testing.T) {
+	type want struct {
+		team    string
+		place   int32
+		score   int32
+		scoring bool
+		sixth   int32
+	}
+	tests := []struct {
+		name  string
+		order string
+		want  []want
+	}{
+		{
+			name:  "two full teams",
+			order: "ABABABABAB",
+			want: []want{
+				{"A", 1, 25, true, 0},
+				{"B", 2, 30, true, 0},
+			},
+		},
+		{
+			name:  "incomplete teams do not score or take places",
+			order: "AAAACBBBBB",
+			want: []want{
+				{"B", 1, 15, true, 0},
+				{"A", 0, 0, false, 0},
+				{"C", 0, 0, false, 0},
+			},
+		},
+		{
+			name:  "sixth and seventh runners displace",
+			order: "AAAAABAABBBB",
+			want: []want{
+				{"A", 1, 15, true, 7},
+				{"B", 2, 48, true, 0},
+			},
+		},
+		{
+			name:  "runners past the seventh do not displace",
+			order: "AAAAAAAABBBBB",
+			want: []want{
+				{"A", 1, 15, true, 6},
+				{"B", 2, 50, true, 0},
+			},
+		},
+		{
+			name:  "tie broken by the sixth runner",
+			order: "BBAAABABAABB",
+			want: []want{
+				{"A", 1, 28, true, 10},
+				{"B", 2, 28, true, 12},
+			},
+		},
+		{
+			name:  "tie lost without a sixth runner",
+			order: "AABBBABABBA",
+			want: []want{
+				{"B", 1, 28, true, 10},
+				{"A", 2, 28, true, 0},
+			},
+		},
+		{
+			name:  "no finishers",
+			order: "",
+			want:  []want{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScoreTeams(finishOrder(tt.order))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d teams, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, w := range tt.want {
+				g := got[i]
+				if g.Team != w.team || g.Place != w.place || g.Score != w.score || g.Scoring != w.scoring || g.SixthRunner != w.sixth {
+					t.Errorf("team %d = {%s place %d score %d scoring %v sixth %d}, want %+v",
+						i, g.Team, g.Place, g.Score, g.Scoring, g.SixthRunner, w)
+				}
+			}
+		})
+	}
+}
+
+func TestScoreTeamsRunners(t *testing.T) {
+	// Eight A runners and five B runners: A's eighth neither scores nor
+	// displaces, so B's runners move up behind A's seventh.
+	teams := ScoreTeams(finishOrder("AAAAAAAABBBBB"))
+	a, b := teams[0], teams[1]
+
+	wantA := []int32{1, 2, 3, 4, 5, 6, 7, 0}
+	for i, p := range teamPlaces(a) {
+		if p != wantA[i] {
+			t.Errorf("A runner %d team place = %d, want %d", i+1, p, wantA[i])
+		}
+		if scorer := i < teamScorers; a.Runners[i].Scorer != scorer {
+			t.Errorf("A runner %d scorer = %v, want %v", i+1, a.Runners[i].Scorer, scorer)
+		}
+	}
+	wantB := []int32{8, 9, 10, 11, 12}
+	for i, p := range teamPlaces(b) {
+		if p != wantB[i] {
+			t.Errorf("B runner %d team place = %d, want %d", i+1, p, wantB[i])
+		}
+	}
+	if a.Finishers != 8 || b.Finishers != 5 {
+		t.Errorf("finishers = %d and %d, want 8 and 5", a.Finishers, b.Finishers)
+	}
+
+	// Runners on a team without five finishers keep their overall place but
+	// take no team place.
+	teams = ScoreTeams(finishOrder("CAAAAA"))
+	c := teams[1]
+	if c.Team != "C" || c.Runners[0].Place != 1 || c.Runners[0].TeamPlace != 0 || c.Runners[0].Scorer {
+		t.Errorf("non-scoring runner = %+v", c.Runners[0])
+	}
+	if got := teamPlaces(teams[0]); got[0] != 1 {
+		t.Errorf("A's first runner took team place %d, want 1", got[0])
+	}
+}
+
+func TestScoreTeamsOrdersByPlaceThenTime(t *testing.T) {
+	finishers := finishOrder("ABABABABAB")
+	// Feed them in reverse with two runners sharing a place, which the
+	// faster time breaks.
+	reversed := make([]Finisher, len(finishers))
+	for i, f := range finishers {
+		reversed[len(finishers)-1-i] = f
+	}
+	reversed[0].Place = 9 // B's fifth runner now ties A's fifth on place
+
+	teams := ScoreTeams(reversed)
+	if teams[0].Team != "A" || teams[0].Score != 25 || teams[1].Score != 30 {
+		t.Fatalf("got %+v", teams)
+	}
+	for _, team := range teams {
+		for i := 1; i < len(team.Runners); i++ {
+			if team.Runners[i].Place < team.Runners[i-1].Place {
+				t.Errorf("%s runners out of order: %+v", team.Team, team.Runners)
+			}
+		}
+	}
+	// The input is left as it was given.
+	if reversed[0].ResultID != 10 {
+		t.Errorf("ScoreTeams reordered its input")
+	}
+}
@@ -0,0 +1,467 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Athlete statuses. Seniors move to alumni when the roster rolls over.
+const (
+	athleteActive = "active"
+	athleteAlumni = "alumni"
+)
+
+type SeasonResponse struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Year      int32  `json:"year"`
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+type CreateSeasonRequest struct {
+	Name      string `json:"name" binding:"required"`
+	Year      int32  `json:"year" binding:"required"`
+	StartDate string `json:"startDate" binding:"required"`
+	EndDate   string `json:"endDate" binding:"required"`
+}
+
+type RosterMemberResponse struct {
+	AthleteID int32  `json:"athleteId"`
+	Name      string `json:"name"`
+	Grade     int32  `json:"grade"`
+	Division  string `json:"division"`
+	Status    string `json:"status"`
+}
+
+type AddRosterMemberRequest struct {
+	AthleteID int32 `json:"athleteId" binding:"required"`
+	Grade     int32 `json:"grade" binding:"required"`
+}
+
+type RolloverResponse struct {
+	SeasonID   int32                  `json:"seasonId"`
+	Promoted   int64                  `json:"promoted"`
+	Graduated  []RosterMemberResponse `json:"graduated"`
+	RosterSize int                    `json:"rosterSize"`
+}
+
+func newSeasonResponse(s db.Season) SeasonResponse {
+	return SeasonResponse{
+		ID:        s.ID,
+		Name:      s.Name,
+		Year:      s.Year,
+		StartDate: s.StartDate.Format("2006-01-02"),
+		EndDate:   s.EndDate.Format("2006-01-02"),
+	}
+}
+
+// parseSeasonDates validates a season's date range.
+func parseSeasonDates(req CreateSeasonRequest) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, badRequest("Invalid start date format, use YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, badRequest("Invalid end date format, use YYYY-MM-DD")
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, badRequest("Season end date is before its start date")
+	}
+	return start, end, nil
+}
+
+// seasonForMeet returns the season a meet belongs to: the one given, which
+// must exist, or else the season whose dates contain the meet date.
+func seasonForMeet(ctx context.Context, seasonID int32, date time.Time) (sql.NullInt32, error) {
+	if seasonID != 0 {
+		if _, err := queries.GetSeasonByID(ctx, seasonID); err != nil {
+			if err == sql.ErrNoRows {
+				return sql.NullInt32{}, badRequest("Season not found")
+			}
+			return sql.NullInt32{}, err
+		}
+		return nullID(seasonID), nil
+	}
+	season, err := queries.GetSeasonForDate(ctx, db.GetSeasonForDateParams{
+		StartDate: date,
+		EndDate:   date,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return sql.NullInt32{}, nil
+		}
+		return sql.NullInt32{}, err
+	}
+	return nullID(season.ID), nil
+}
+
+// gradeAt returns the grade an athlete was in for a meet: their roster grade
+// for the meet's season when there is one, or their current grade.
+func gradeAt(current int32, seasonGrade sql.NullInt32) int32 {
+	if seasonGrade.Valid {
+		return seasonGrade.Int32
+	}
+	return current
+}
+
+// rolloverRoster moves the active roster into a new season. The previous
+// season's roster is filled in first so results keep the grade athletes were
+// in at the time, then seniors graduate to alumni and everyone else moves up
+// a grade. Only the newest season can be rolled into, since rolling into an
+// older one would promote athletes past the grades they are in now.
+func rolloverRoster(ctx context.Context, q *db.Queries, season db.Season) (RolloverResponse, error) {
+	response := RolloverResponse{SeasonID: season.ID, Graduated: []RosterMemberResponse{}}
+
+	latest, err := q.GetLatestSeason(ctx)
+	if err != nil {
+		return response, err
+	}
+	if latest.ID != season.ID {
+		return response, badRequest(fmt.Sprintf("Only the newest season, %s, can be rolled over into", latest.Name))
+	}
+
+	count, err := q.CountSeasonRoster(ctx, season.ID)
+	if err != nil {
+		return response, err
+	}
+	if count > 0 {
+		return response, badRequest("Season already has a roster")
+	}
+
+	athletes, err := q.GetActiveAthletes(ctx)
+	if err != nil {
+		return response, err
+	}
+
+	previous, err := q.GetPreviousSeason(ctx, season.Year)
+	if err != nil && err != sql.ErrNoRows {
+		return response, err
+	}
+	hasPrevious := err == nil
+	for _, a := range athletes {
+		if hasPrevious {
+			err := q.AddRosterMemberIfMissing(ctx, db.AddRosterMemberIfMissingParams{
+				SeasonID:  previous.ID,
+				AthleteID: a.ID,
+				Grade:     a.Grade,
+			})
+			if err != nil {
+				return response, err
+			}
+		}
+		if a.Grade >= 12 {
+			response.Graduated = append(response.Graduated, RosterMemberResponse{
+				AthleteID: a.ID,
+				Name:      a.Name,
+				Grade:     a.Grade,
+				Division:  a.Division,
+				Status:    athleteAlumni,
+			})
+		}
+	}
+
+	if _, err := q.GraduateSeniors(ctx); err != nil {
+		return response, err
+	}
+	if response.Promoted, err = q.PromoteActiveAthletes(ctx); err != nil {
+		return response, err
+	}
+
+	athletes, err = q.GetActiveAthletes(ctx)
+	if err != nil {
+		return response, err
+	}
+	for _, a := range athletes {
+		err := q.AddRosterMember(ctx, db.AddRosterMemberParams{
+			SeasonID:  season.ID,
+			AthleteID: a.ID,
+			Grade:     a.Grade,
+		})
+		if err != nil {
+			return response, err
+		}
+	}
+	response.RosterSize = len(athletes)
+	return response, nil
+}
+
+func registerSeasonRoutes(r *gin.Engine) {
+	// Get all seasons, newest first
+	r.GET("/api/seasons", func(c *gin.Context) {
+		seasons, err := queries.GetAllSeasons(context.Background())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]SeasonResponse, len(seasons))
+		for i, s := range seasons {
+			response[i] = newSeasonResponse(s)
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Get season by ID
+	r.GET("/api/seasons/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+			return
+		}
+
+		season, err := queries.GetSeasonByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, newSeasonResponse(season))
+	})
+
+	// Create a new season
+	r.POST("/api/seasons", func(c *gin.Context) {
+		var req CreateSeasonRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		start, end, err := parseSeasonDates(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := queries.CreateSeason(context.Background(), db.CreateSeasonParams{
+			Name:      req.Name,
+			Year:      req.Year,
+			StartDate: start,
+			EndDate:   end,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		id, _ := result.LastInsertId()
+		c.JSON(http.StatusCreated, gin.H{"id": id, "message": "Season created successfully"})
+	})
+
+	// Update a season
+	r.PUT("/api/seasons/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+			return
+		}
+
+		var req CreateSeasonRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		start, end, err := parseSeasonDates(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = queries.UpdateSeason(context.Background(), db.UpdateSeasonParams{
+			ID:        int32(id),
+			Name:      req.Name,
+			Year:      req.Year,
+			StartDate: start,
+			EndDate:   end,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Season updated successfully"})
+	})
+
+	// Delete a season; its meets are kept but unassigned
+	r.DELETE("/api/seasons/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+			return
+		}
+
+		err = queries.DeleteSeason(context.Background(), int32(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Season deleted successfully"})
+	})
+
+	// Get the meets in a season
+	r.GET("/api/seasons/:id/meets", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+			return
+		}
+
+		meets, err := queries.GetSeasonMeets(context.Background(), nullID(int32(id)))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]MeetResponse, len(meets))
+		for i, m := range meets {
+			response[i] = newMeetResponse(m)
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Get a season's roster with each athlete's grade that season
+	r.GET("/api/seasons/:id/roster", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+			return
+		}
+
+		roster, err := queries.GetSeasonRoster(context.Background(), int32(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]RosterMemberResponse, len(roster))
+		for i, m := range roster {
+			response[i] = RosterMemberResponse{
+				AthleteID: m.ID,
+				Name:      m.Name,
+				Grade:     m.Grade,
+				Division:  m.Division,
+				Status:    m.Status,
+			}
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Add an athlete to a season's roster
+	r.POST("/api/seasons/:id/roster", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+			return
+		}
+
+		var req AddRosterMemberRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		season, err := queries.GetSeasonByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		athlete, err := homeAthlete(context.Background(), queries, req.AthleteID)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		err = queries.AddRosterMember(context.Background(), db.AddRosterMemberParams{
+			SeasonID:  season.ID,
+			AthleteID: athlete.ID,
+			Grade:     req.Grade,
+		})
+		if err != nil {
+			if isDuplicateKey(err) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s is already on the %s roster", athlete.Name, season.Name)})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Athlete added to roster"})
+	})
+
+	// Remove an athlete from a season's roster
+	r.DELETE("/api/seasons/:id/roster/:athleteId", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+			return
+		}
+		athleteID, err := strconv.Atoi(c.Param("athleteId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid athlete ID"})
+			return
+		}
+
+		err = queries.RemoveRosterMember(context.Background(), db.RemoveRosterMemberParams{
+			SeasonID:  int32(id),
+			AthleteID: int32(athleteID),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Athlete removed from roster"})
+	})
+
+	// Roll the active roster forward into a new season. Graduating and
+	// promoting the whole roster is left to admins.
+	r.POST("/api/seasons/:id/rollover", requireRole(roleAdmin), func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+			return
+		}
+
+		season, err := queries.GetSeasonByID(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		tx, err := conn.BeginTx(context.Background(), nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback()
+
+		response, err := rolloverRoster(context.Background(), queries.WithTx(tx), season)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	})
+}
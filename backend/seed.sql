@@ -1,52 +1,91 @@
 -- Sample athletes
-INSERT INTO athletes (name, grade, personal_record, events) VALUES
-('Marcus Thompson', 12, '16:23', '5K,3200m'),
-('Jake Reynolds', 11, '17:05', '5K,1600m'),
-('Dylan Carter', 10, '17:48', '5K'),
-('Chris Nguyen', 9, '18:32', '5K,3200m'),
-('Brandon Scott', 12, '16:45', '5K,1600m'),
-('Emily Davis', 11, '19:15', '5K,3200m'),
-('Sarah Mitchell', 12, '18:42', '5K,1600m'),
-('Mia Rodriguez', 10, '20:08', '5K'),
-('Hannah Clark', 11, '19:55', '5K,3200m'),
-('Lily Patterson', 9, '21:30', '5K');
+INSERT INTO athletes (name, grade, division, school_id, events) VALUES
+('Marcus Thompson', 12, 'boys', 1, '5K,3200m'),
+('Jake Reynolds', 11, 'boys', 1, '5K,1600m'),
+('Dylan Carter', 10, 'boys', 1, '5K'),
+('Chris Nguyen', 9, 'boys', 1, '5K,3200m'),
+('Brandon Scott', 12, 'boys', 1, '5K,1600m'),
+('Emily Davis', 11, 'girls', 1, '5K,3200m'),
+('Sarah Mitchell', 12, 'girls', 1, '5K,1600m'),
+('Mia Rodriguez', 10, 'girls', 1, '5K'),
+('Hannah Clark', 11, 'girls', 1, '5K,3200m'),
+('Lily Patterson', 9, 'girls', 1, '5K');
+
+-- Sample season and its roster
+INSERT INTO seasons (name, year, start_date, end_date) VALUES
+('2026 Cross Country', 2026, '2026-08-01', '2026-11-30');
+
+INSERT INTO season_rosters (season_id, athlete_id, grade) VALUES
+(1, 1, 12),
+(1, 2, 11),
+(1, 3, 10),
+(1, 4, 9),
+(1, 5, 12),
+(1, 6, 11),
+(1, 7, 12),
+(1, 8, 10),
+(1, 9, 11),
+(1, 10, 9);
+
+-- Sample courses
+INSERT INTO courses (name, venue, distance_meters, surface, latitude, longitude, notes) VALUES
+('Panther Creek XC Course', 'Panther Creek State Park, Covington, GA', 5000, 'grass/dirt trail', 33.6012, -83.8609, 'Rolling first mile, long climb before 2 miles'),
+('Berkmar XC Course', 'Berkmar High School, Lilburn, GA', 5000, 'grass', 33.8904, -84.1358, NULL),
+('Grayson XC Course', 'Grayson High School, Loganville, GA', 5000, 'grass', 33.8621, -83.9571, 'Fast, mostly flat loops'),
+('Carrollton XC Course', 'Carrollton, GA', 5000, 'grass/dirt trail', 33.5801, -85.0766, 'Home of the GHSA state championship'),
+('Jones County XC Course', 'Jones County Recreation Complex, Gray, GA', 5000, 'grass', 33.0098, -83.5332, 'Home course');
 
 -- Sample meets
-INSERT INTO meets (name, date, location, description) VALUES
-('Panther Creek Invitational', '2026-08-22', 'Panther Creek State Park, Covington, GA', 'Season opener hosted by Newton County'),
-('Run the Bison Classic', '2026-09-05', 'Berkmar High School, Lilburn, GA', 'Large invitational with 30+ schools'),
-('Grayson Invitational', '2026-09-12', 'Grayson High School, Loganville, GA', 'Competitive meet featuring top metro Atlanta teams'),
-('West Georgia Invitational', '2026-09-26', 'Carrollton, GA', 'Western Georgia regional competition'),
-('Region 4-AAAAA Championship', '2026-10-17', 'Jones County Recreation Complex, Gray, GA', 'Region championship meet'),
-('GHSA State Championship', '2026-11-07', 'Carrollton, GA', 'Georgia High School State Championship');
+INSERT INTO meets (name, date, location, course_id, season_id, description) VALUES
+('Panther Creek Invitational', '2026-08-22', 'Panther Creek State Park, Covington, GA', 1, 1, 'Season opener hosted by Newton County'),
+('Run the Bison Classic', '2026-09-05', 'Berkmar High School, Lilburn, GA', 2, 1, 'Large invitational with 30+ schools'),
+('Grayson Invitational', '2026-09-12', 'Grayson High School, Loganville, GA', 3, 1, 'Competitive meet featuring top metro Atlanta teams'),
+('West Georgia Invitational', '2026-09-26', 'Carrollton, GA', 4, 1, 'Western Georgia regional competition'),
+('Region 4-AAAAA Championship', '2026-10-17', 'Jones County Recreation Complex, Gray, GA', 5, 1, 'Region championship meet'),
+('GHSA State Championship', '2026-11-07', 'Carrollton, GA', 4, 1, 'Georgia High School State Championship');
+
+-- Sample races (boys and girls varsity at every meet)
+INSERT INTO races (meet_id, name, distance_meters, start_time) VALUES
+(1, 'Varsity Boys 5K', 5000, '2026-08-22 08:00:00'),
+(1, 'Varsity Girls 5K', 5000, '2026-08-22 08:45:00'),
+(2, 'Varsity Boys 5K', 5000, '2026-09-05 08:00:00'),
+(2, 'Varsity Girls 5K', 5000, '2026-09-05 08:45:00'),
+(3, 'Varsity Boys 5K', 5000, '2026-09-12 08:30:00'),
+(3, 'Varsity Girls 5K', 5000, '2026-09-12 09:15:00'),
+(4, 'Varsity Boys 5K', 5000, '2026-09-26 08:00:00'),
+(4, 'Varsity Girls 5K', 5000, '2026-09-26 08:45:00'),
+(5, 'Varsity Boys 5K', 5000, '2026-10-17 09:00:00'),
+(5, 'Varsity Girls 5K', 5000, '2026-10-17 09:45:00'),
+(6, 'Varsity Boys 5K', 5000, '2026-11-07 10:00:00'),
+(6, 'Varsity Girls 5K', 5000, '2026-11-07 11:00:00');
 
 -- Sample results for Panther Creek Invitational
-INSERT INTO results (athlete_id, meet_id, time, place) VALUES
-(1, 1, '16:45', 3),
-(2, 1, '17:22', 8),
-(3, 1, '18:05', 15),
-(4, 1, '19:01', 24),
-(5, 1, '17:02', 5),
-(6, 1, '19:38', 4),
-(7, 1, '19:02', 2),
-(8, 1, '20:45', 12),
-(9, 1, '20:18', 8),
-(10, 1, '22:05', 18);
+INSERT INTO results (athlete_id, meet_id, race_id, time_ms, place) VALUES
+(1, 1, 1, 1005000, 3),
+(2, 1, 1, 1042000, 8),
+(3, 1, 1, 1085000, 15),
+(4, 1, 1, 1141000, 24),
+(5, 1, 1, 1022000, 5),
+(6, 1, 2, 1178000, 4),
+(7, 1, 2, 1142000, 2),
+(8, 1, 2, 1245000, 12),
+(9, 1, 2, 1218000, 8),
+(10, 1, 2, 1325000, 18);
 
 -- Sample results for Run the Bison Classic
-INSERT INTO results (athlete_id, meet_id, time, place) VALUES
-(1, 2, '16:31', 5),
-(2, 2, '17:15', 18),
-(3, 2, '17:55', 32),
-(5, 2, '16:52', 12),
-(6, 2, '19:22', 8),
-(7, 2, '18:55', 3),
-(9, 2, '20:02', 15);
+INSERT INTO results (athlete_id, meet_id, race_id, time_ms, place) VALUES
+(1, 2, 3, 991000, 5),
+(2, 2, 3, 1035000, 18),
+(3, 2, 3, 1075000, 32),
+(5, 2, 3, 1012000, 12),
+(6, 2, 4, 1162000, 8),
+(7, 2, 4, 1135000, 3),
+(9, 2, 4, 1202000, 15);
 
 -- Sample results for Grayson Invitational
-INSERT INTO results (athlete_id, meet_id, time, place) VALUES
-(1, 3, '16:28', 7),
-(2, 3, '17:08', 22),
-(5, 3, '16:48', 14),
-(7, 3, '18:48', 5),
-(6, 3, '19:30', 9);
+INSERT INTO results (athlete_id, meet_id, race_id, time_ms, place) VALUES
+(1, 3, 5, 988000, 7),
+(2, 3, 5, 1028000, 22),
+(5, 3, 5, 1008000, 14),
+(7, 3, 6, 1128000, 5),
+(6, 3, 6, 1170000, 9);
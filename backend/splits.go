@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// evenSplitTolerance is how close the two halves of a race must be for it to
+// count as evenly split rather than positive or negative.
+const evenSplitTolerance RaceTime = 2000
+
+// Split types, comparing the second half of a race with the first.
+const (
+	splitNegative = "negative"
+	splitPositive = "positive"
+	splitEven     = "even"
+)
+
+type SplitRequest struct {
+	DistanceMeters int32    `json:"distanceMeters" binding:"required,gt=0"`
+	Time           RaceTime `json:"time" binding:"required"`
+}
+
+// RecordSplitsRequest replaces a result's splits. They are listed in race
+// order; the finish is added from the result's time if it is left out.
+type RecordSplitsRequest struct {
+	Splits []SplitRequest `json:"splits" binding:"required,min=1,dive"`
+}
+
+// SplitResponse is one checkpoint along with the segment run since the
+// previous one.
+type SplitResponse struct {
+	DistanceMeters     int32    `json:"distanceMeters"`
+	Time               RaceTime `json:"time"`
+	SegmentMeters      int32    `json:"segmentMeters"`
+	SegmentTime        RaceTime `json:"segmentTime"`
+	SegmentPacePerMile RaceTime `json:"segmentPacePerMile"`
+}
+
+// SplitAnalysis compares the two halves of a race. When no checkpoint falls
+// exactly at halfway, the halfway time is interpolated from the checkpoints
+// either side. A negative DifferenceSeconds means the second half was faster.
+type SplitAnalysis struct {
+	FirstHalf         RaceTime `json:"firstHalf"`
+	SecondHalf        RaceTime `json:"secondHalf"`
+	DifferenceSeconds float64  `json:"differenceSeconds"`
+	Type              string   `json:"type"`
+}
+
+type SplitsResponse struct {
+	ResultID       int32           `json:"resultId"`
+	DistanceMeters int32           `json:"distanceMeters"`
+	Time           RaceTime        `json:"time"`
+	Splits         []SplitResponse `json:"splits"`
+	Analysis       *SplitAnalysis  `json:"analysis"`
+}
+
+// checkpoint is an elapsed time at a distance into a race.
+type checkpoint struct {
+	Distance int32
+	Elapsed  RaceTime
+}
+
+// completeSplits checks that splits increase in both distance and time and
+// end at the finish of a race run in final, adding the finish when it is
+// missing.
+func completeSplits(splits []checkpoint, distance int32, final RaceTime) ([]checkpoint, error) {
+	var prev checkpoint
+	for i, s := range splits {
+		if s.Distance <= prev.Distance {
+			return nil, badRequest(fmt.Sprintf("Split %d must be further than the one before it", i+1))
+		}
+		if s.Elapsed <= prev.Elapsed {
+			return nil, badRequest(fmt.Sprintf("Split %d must be later than the one before it", i+1))
+		}
+		if s.Distance > distance {
+			return nil, badRequest(fmt.Sprintf("Split %d is beyond the %dm race distance", i+1, distance))
+		}
+		prev = s
+	}
+
+	if prev.Distance == distance {
+		if prev.Elapsed != final {
+			return nil, badRequest(fmt.Sprintf("The finish split must match the final time of %s", final))
+		}
+		return splits, nil
+	}
+	if prev.Elapsed >= final {
+		return nil, badRequest(fmt.Sprintf("Splits must be faster than the final time of %s", final))
+	}
+	return append(splits, checkpoint{Distance: distance, Elapsed: final}), nil
+}
+
+// analyzeSplits works out each segment's time and pace and how the race was
+// split. The last checkpoint must be the finish.
+func analyzeSplits(splits []checkpoint) ([]SplitResponse, SplitAnalysis) {
+	segments := make([]SplitResponse, len(splits))
+	var prev checkpoint
+	for i, s := range splits {
+		segment := s.Elapsed - prev.Elapsed
+		segments[i] = SplitResponse{
+			DistanceMeters:     s.Distance,
+			Time:               s.Elapsed,
+			SegmentMeters:      s.Distance - prev.Distance,
+			SegmentTime:        segment,
+			SegmentPacePerMile: segment.Pace(s.Distance-prev.Distance, metersPerMile),
+		}
+		prev = s
+	}
+
+	finish := splits[len(splits)-1]
+	half := float64(finish.Distance) / 2
+	var halfway RaceTime
+	prev = checkpoint{}
+	for _, s := range splits {
+		if float64(s.Distance) >= half {
+			fraction := (half - float64(prev.Distance)) / float64(s.Distance-prev.Distance)
+			halfway = prev.Elapsed + RaceTime(math.Round(float64(s.Elapsed-prev.Elapsed)*fraction))
+			break
+		}
+		prev = s
+	}
+
+	analysis := SplitAnalysis{
+		FirstHalf:  halfway,
+		SecondHalf: finish.Elapsed - halfway,
+	}
+	diff := analysis.SecondHalf - analysis.FirstHalf
+	analysis.DifferenceSeconds = diff.Seconds()
+	switch {
+	case diff > evenSplitTolerance:
+		analysis.Type = splitPositive
+	case diff < -evenSplitTolerance:
+		analysis.Type = splitNegative
+	default:
+		analysis.Type = splitEven
+	}
+	return segments, analysis
+}
+
+func checkpointsFromSplits(splits []db.Split) []checkpoint {
+	checkpoints := make([]checkpoint, len(splits))
+	for i, s := range splits {
+		checkpoints[i] = checkpoint{Distance: s.DistanceMeters, Elapsed: RaceTime(s.ElapsedMs)}
+	}
+	return checkpoints
+}
+
+// dropStaleSplits removes a result's splits once they no longer fit it, for
+// instance after its time, race or status is corrected.
+func dropStaleSplits(ctx context.Context, q *db.Queries, resultID int32) error {
+	splits, err := q.GetResultSplits(ctx, resultID)
+	if err != nil || len(splits) == 0 {
+		return err
+	}
+	result, err := q.GetResultRace(ctx, resultID)
+	if err != nil {
+		return err
+	}
+
+	if result.Status == statusFinished {
+		stored := checkpointsFromSplits(splits)
+		completed, err := completeSplits(stored, result.DistanceMeters, RaceTime(result.TimeMs.Int32))
+		if err == nil && len(completed) == len(stored) {
+			return nil
+		}
+	}
+	return q.DeleteResultSplits(ctx, resultID)
+}
+
+func registerSplitRoutes(r *gin.Engine) {
+	// Get a result's splits with segment paces and split analysis
+	r.GET("/api/results/:id/splits", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid result ID"})
+			return
+		}
+
+		result, err := queries.GetResultRace(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Result not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		splits, err := queries.GetResultSplits(context.Background(), result.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := SplitsResponse{
+			ResultID:       result.ID,
+			DistanceMeters: result.DistanceMeters,
+			Time:           RaceTime(result.TimeMs.Int32),
+			Splits:         []SplitResponse{},
+		}
+		if len(splits) > 0 {
+			segments, analysis := analyzeSplits(checkpointsFromSplits(splits))
+			response.Splits = segments
+			response.Analysis = &analysis
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Record a result's splits, replacing any already saved
+	r.PUT("/api/results/:id/splits", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid result ID"})
+			return
+		}
+
+		var req RecordSplitsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := queries.GetResultRace(context.Background(), int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Result not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if result.Status != statusFinished {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only finished results have splits"})
+			return
+		}
+
+		requested := make([]checkpoint, len(req.Splits))
+		for i, s := range req.Splits {
+			requested[i] = checkpoint{Distance: s.DistanceMeters, Elapsed: s.Time}
+		}
+		splits, err := completeSplits(requested, result.DistanceMeters, RaceTime(result.TimeMs.Int32))
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		tx, err := conn.BeginTx(context.Background(), nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback()
+		qtx := queries.WithTx(tx)
+
+		if err := qtx.DeleteResultSplits(context.Background(), result.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		for _, s := range splits {
+			err := qtx.CreateSplit(context.Background(), db.CreateSplitParams{
+				ResultID:       result.ID,
+				DistanceMeters: s.Distance,
+				ElapsedMs:      s.Elapsed.Milliseconds(),
+			})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Splits recorded successfully"})
+	})
+
+	// Delete a result's splits
+	r.DELETE("/api/results/:id/splits", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid result ID"})
+			return
+		}
+
+		err = queries.DeleteResultSplits(context.Background(), int32(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Splits deleted successfully"})
+	})
+}
@@ -0,0 +1,565 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timing session statuses. Taps are recorded and edited while a session is
+// running; once finalized its results have been saved.
+const (
+	timingRunning   = "running"
+	timingFinalized = "finalized"
+)
+
+// timingClockLayout is the format a session's start time is sent in.
+const timingClockLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// StartTimingRequest starts the clock for a race. The race may be omitted
+// when the meet has exactly one.
+type StartTimingRequest struct {
+	RaceID int32 `json:"raceId"`
+}
+
+// RecordTapsRequest records finishers crossing the line together. The body
+// may be left out to record a single tap.
+type RecordTapsRequest struct {
+	Count int `json:"count" binding:"omitempty,min=1,max=20"`
+}
+
+type CorrectTapRequest struct {
+	Time RaceTime `json:"time" binding:"required"`
+}
+
+// AssignTapRequest names the athlete behind a tap by exactly one of their ID,
+// their bib number for the meet, or their name.
+type AssignTapRequest struct {
+	AthleteID int32  `json:"athleteId"`
+	Bib       int32  `json:"bib"`
+	Name      string `json:"name"`
+}
+
+// TimingTapResponse is one finisher. Place follows finish order, so it
+// changes as taps are corrected or deleted.
+type TimingTapResponse struct {
+	ID          int32    `json:"id"`
+	Place       int32    `json:"place"`
+	Time        RaceTime `json:"time"`
+	AthleteID   int32    `json:"athleteId"`
+	AthleteName string   `json:"athleteName"`
+}
+
+// TimingSessionResponse describes a session. Taps are left out when sessions
+// are listed.
+type TimingSessionResponse struct {
+	ID        int32               `json:"id"`
+	MeetID    int32               `json:"meetId"`
+	RaceID    int32               `json:"raceId"`
+	RaceName  string              `json:"raceName"`
+	Status    string              `json:"status"`
+	StartedAt string              `json:"startedAt"`
+	Taps      []TimingTapResponse `json:"taps,omitempty"`
+}
+
+type RecordedTap struct {
+	ID   int64    `json:"id"`
+	Time RaceTime `json:"time"`
+}
+
+func newTimingSessionResponse(s db.GetTimingSessionRow) TimingSessionResponse {
+	return TimingSessionResponse{
+		ID:        s.ID,
+		MeetID:    s.MeetID,
+		RaceID:    s.RaceID,
+		RaceName:  s.RaceName,
+		Status:    s.Status,
+		StartedAt: s.StartedAt.Format(timingClockLayout),
+	}
+}
+
+func newTimingTapResponses(taps []db.GetTimingTapsRow) []TimingTapResponse {
+	response := make([]TimingTapResponse, len(taps))
+	for i, t := range taps {
+		response[i] = TimingTapResponse{
+			ID:          t.ID,
+			Place:       int32(i + 1),
+			Time:        RaceTime(t.ElapsedMs),
+			AthleteID:   t.AthleteID.Int32,
+			AthleteName: t.AthleteName.String,
+		}
+	}
+	return response
+}
+
+// timingSession loads the session named in a route. Unless running is false,
+// a finalized session is refused since it can no longer change.
+func timingSession(c *gin.Context, running bool) (db.GetTimingSessionRow, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timing session ID"})
+		return db.GetTimingSessionRow{}, false
+	}
+	session, err := queries.GetTimingSession(context.Background(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Timing session not found"})
+			return db.GetTimingSessionRow{}, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return db.GetTimingSessionRow{}, false
+	}
+	if running && session.Status != timingRunning {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Timing session is already finalized"})
+		return db.GetTimingSessionRow{}, false
+	}
+	return session, true
+}
+
+// sessionTap loads a session's taps and finds the one named in a route,
+// returning its index in finish order.
+func sessionTap(c *gin.Context, session db.GetTimingSessionRow) ([]db.GetTimingTapsRow, int, bool) {
+	tapID, err := strconv.Atoi(c.Param("tapId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tap ID"})
+		return nil, 0, false
+	}
+	taps, err := queries.GetTimingTaps(context.Background(), session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, 0, false
+	}
+	for i, t := range taps {
+		if t.ID == int32(tapID) {
+			return taps, i, true
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "Tap not found"})
+	return nil, 0, false
+}
+
+// tapAthlete finds the athlete an assignment names. Names are matched the
+// same way as imported results, against the athletes entered in the race or,
+// if nobody is, the whole active roster.
+func tapAthlete(ctx context.Context, session db.GetTimingSessionRow, req AssignTapRequest) (int32, string, error) {
+	given := 0
+	for _, set := range []bool{req.AthleteID != 0, req.Bib != 0, req.Name != ""} {
+		if set {
+			given++
+		}
+	}
+	if given != 1 {
+		return 0, "", badRequest("Give one of athleteId, bib or name")
+	}
+
+	switch {
+	case req.AthleteID != 0:
+		athlete, err := queries.GetAthleteByID(ctx, req.AthleteID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return 0, "", badRequest("Athlete not found")
+			}
+			return 0, "", err
+		}
+		return athlete.ID, athlete.Name, nil
+
+	case req.Bib != 0:
+		entry, err := queries.GetEntryByBib(ctx, db.GetEntryByBibParams{
+			MeetID: session.MeetID,
+			Bib:    sql.NullInt32{Int32: req.Bib, Valid: true},
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return 0, "", badRequest(fmt.Sprintf("No athlete is wearing bib %d", req.Bib))
+			}
+			return 0, "", err
+		}
+		return entry.AthleteID, entry.AthleteName, nil
+	}
+
+	athletes, err := queries.GetActiveAthletes(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+	entries, err := queries.GetMeetEntries(ctx, session.MeetID)
+	if err != nil {
+		return 0, "", err
+	}
+	inRace := make(map[int32]bool)
+	for _, e := range entries {
+		if e.RaceID == session.RaceID {
+			inRace[e.AthleteID] = true
+		}
+	}
+	if len(inRace) > 0 {
+		var entered []db.Athlete
+		for _, a := range athletes {
+			if inRace[a.ID] {
+				entered = append(entered, a)
+			}
+		}
+		athletes = entered
+	}
+
+	row := ImportRow{Name: req.Name}
+	matchAthlete(&row, athletes)
+	switch row.Status {
+	case importAmbiguous:
+		return 0, "", badRequest(fmt.Sprintf("%q matches several athletes: %s", req.Name, strings.Join(row.Candidates, ", ")))
+	case importUnknown:
+		return 0, "", badRequest(fmt.Sprintf("No athlete matches %q", req.Name))
+	}
+	return row.AthleteID, row.AthleteName, nil
+}
+
+func registerTimingRoutes(r *gin.Engine) {
+	// Start the clock for a race in a meet
+	r.POST("/api/meets/:id/timing", func(c *gin.Context) {
+		now := time.Now()
+		meet, ok := meetParam(c)
+		if !ok {
+			return
+		}
+
+		var req StartTimingRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		race, err := resolveRace(context.Background(), queries, meet.ID, req.RaceID)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		running, err := queries.CountRunningTimingSessions(context.Background(), race.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if running > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": race.Name + " already has a running timing session"})
+			return
+		}
+
+		result, err := queries.CreateTimingSession(context.Background(), db.CreateTimingSessionParams{
+			MeetID:    meet.ID,
+			RaceID:    race.ID,
+			StartedAt: now,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		id, _ := result.LastInsertId()
+		c.JSON(http.StatusCreated, gin.H{
+			"id":        id,
+			"startedAt": now.Format(timingClockLayout),
+			"message":   "Timing session started successfully",
+		})
+	})
+
+	// Get a meet's timing sessions
+	r.GET("/api/meets/:id/timing", func(c *gin.Context) {
+		meet, ok := meetParam(c)
+		if !ok {
+			return
+		}
+
+		sessions, err := queries.GetMeetTimingSessions(context.Background(), meet.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]TimingSessionResponse, len(sessions))
+		for i, s := range sessions {
+			response[i] = newTimingSessionResponse(db.GetTimingSessionRow(s))
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Get a timing session with its taps in finish order
+	r.GET("/api/timing/:id", func(c *gin.Context) {
+		session, ok := timingSession(c, false)
+		if !ok {
+			return
+		}
+
+		taps, err := queries.GetTimingTaps(context.Background(), session.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := newTimingSessionResponse(session)
+		response.Taps = newTimingTapResponses(taps)
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Discard a timing session and its taps. Results already saved from it
+	// are kept.
+	r.DELETE("/api/timing/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timing session ID"})
+			return
+		}
+
+		err = queries.DeleteTimingSession(context.Background(), int32(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Timing session deleted successfully"})
+	})
+
+	// Record finishers crossing the line. Each tap is timed by the server
+	// when the request arrives and saved on its own, so taps sent in quick
+	// succession or out of order still line up by time.
+	r.POST("/api/timing/:id/taps", func(c *gin.Context) {
+		now := time.Now()
+		session, ok := timingSession(c, true)
+		if !ok {
+			return
+		}
+
+		var req RecordTapsRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Count == 0 {
+			req.Count = 1
+		}
+
+		elapsed := RaceTime(now.Sub(session.StartedAt).Milliseconds())
+		recorded := make([]RecordedTap, req.Count)
+		for i := range recorded {
+			result, err := queries.CreateTimingTap(context.Background(), db.CreateTimingTapParams{
+				SessionID:  session.ID,
+				ElapsedMs:  elapsed.Milliseconds(),
+				RecordedAt: now,
+			})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			recorded[i].ID, _ = result.LastInsertId()
+			recorded[i].Time = elapsed
+		}
+		c.JSON(http.StatusCreated, gin.H{"taps": recorded, "message": fmt.Sprintf("%d taps recorded successfully", len(recorded))})
+	})
+
+	// Correct a tap's time
+	r.PUT("/api/timing/:id/taps/:tapId", func(c *gin.Context) {
+		session, ok := timingSession(c, true)
+		if !ok {
+			return
+		}
+		taps, i, ok := sessionTap(c, session)
+		if !ok {
+			return
+		}
+
+		var req CorrectTapRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err := queries.UpdateTimingTapTime(context.Background(), db.UpdateTimingTapTimeParams{
+			ElapsedMs: req.Time.Milliseconds(),
+			ID:        taps[i].ID,
+			SessionID: session.ID,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Tap updated successfully"})
+	})
+
+	// Delete a tap, such as a double press
+	r.DELETE("/api/timing/:id/taps/:tapId", func(c *gin.Context) {
+		session, ok := timingSession(c, true)
+		if !ok {
+			return
+		}
+		taps, i, ok := sessionTap(c, session)
+		if !ok {
+			return
+		}
+
+		err := queries.DeleteTimingTap(context.Background(), db.DeleteTimingTapParams{
+			ID:        taps[i].ID,
+			SessionID: session.ID,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Tap deleted successfully"})
+	})
+
+	// Assign the athlete behind a tap by ID, bib or name
+	r.PUT("/api/timing/:id/taps/:tapId/athlete", func(c *gin.Context) {
+		session, ok := timingSession(c, true)
+		if !ok {
+			return
+		}
+		taps, i, ok := sessionTap(c, session)
+		if !ok {
+			return
+		}
+
+		var req AssignTapRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		athleteID, name, err := tapAthlete(context.Background(), session, req)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		for place, t := range taps {
+			if t.AthleteID.Int32 == athleteID && place != i {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s is already assigned to place %d", name, place+1)})
+				return
+			}
+		}
+
+		err = queries.AssignTimingTap(context.Background(), db.AssignTimingTapParams{
+			AthleteID: nullID(athleteID),
+			ID:        taps[i].ID,
+			SessionID: session.ID,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"athleteId": athleteID, "athleteName": name, "message": "Tap assigned successfully"})
+	})
+
+	// Clear the athlete assigned to a tap
+	r.DELETE("/api/timing/:id/taps/:tapId/athlete", func(c *gin.Context) {
+		session, ok := timingSession(c, true)
+		if !ok {
+			return
+		}
+		taps, i, ok := sessionTap(c, session)
+		if !ok {
+			return
+		}
+
+		err := queries.AssignTimingTap(context.Background(), db.AssignTimingTapParams{
+			ID:        taps[i].ID,
+			SessionID: session.ID,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Tap unassigned successfully"})
+	})
+
+	// Save a session's taps as results, placed in finish order. Every tap
+	// must have an athlete, and nothing is saved unless every result is valid.
+	r.POST("/api/timing/:id/finalize", func(c *gin.Context) {
+		session, ok := timingSession(c, true)
+		if !ok {
+			return
+		}
+
+		taps, err := queries.GetTimingTaps(context.Background(), session.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(taps) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Timing session has no taps"})
+			return
+		}
+
+		rows := make([]BulkResultRow, len(taps))
+		for i, t := range taps {
+			if !t.AthleteID.Valid {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Place %d has no athlete assigned", i+1)})
+				return
+			}
+			rows[i] = BulkResultRow{
+				AthleteID: t.AthleteID.Int32,
+				Status:    statusFinished,
+				Time:      RaceTime(t.ElapsedMs).String(),
+				Place:     int32(i + 1),
+			}
+		}
+
+		// Closing the session first locks it, so a second finalize waits for
+		// this one and then finds nothing left to close.
+		ctx := context.Background()
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback()
+		q := queries.WithTx(tx)
+
+		closed, err := q.FinalizeTimingSession(ctx, session.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if closed == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Timing session is already finalized"})
+			return
+		}
+
+		requests, rowErrors, err := validateBulkResults(ctx, q, session.MeetID, BulkResultsRequest{
+			RaceID:  session.RaceID,
+			Results: rows,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(rowErrors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No results were saved", "rows": rowErrors})
+			return
+		}
+
+		created, err := insertResults(ctx, q, requests)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		publishCreated(ctx, created)
+
+		response := make([]BulkResultResponse, len(created))
+		for i, cr := range created {
+			response[i] = BulkResultResponse{Row: i + 1, CreatedResult: cr}
+		}
+		c.JSON(http.StatusCreated, gin.H{
+			"message": fmt.Sprintf("%d results created successfully", len(created)),
+			"results": response,
+		})
+	})
+}
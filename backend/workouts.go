@@ -0,0 +1,506 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"jones-county-xc/backend/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkoutResponse struct {
+	ID             int32    `json:"id"`
+	AthleteID      int32    `json:"athleteId"`
+	Date           string   `json:"date"`
+	Type           string   `json:"type"`
+	DistanceMeters int32    `json:"distanceMeters"`
+	Miles          float64  `json:"miles"`
+	Duration       RaceTime `json:"duration"`
+	PacePerMile    RaceTime `json:"pacePerMile"`
+	Effort         int32    `json:"effort"`
+	Notes          string   `json:"notes"`
+}
+
+// CreateWorkoutRequest logs a workout. It needs a distance, a duration or
+// both, so cross-training can be logged by duration alone.
+type CreateWorkoutRequest struct {
+	Date           string   `json:"date" binding:"required"`
+	Type           string   `json:"type" binding:"required,oneof=easy long tempo intervals recovery race cross"`
+	DistanceMeters int32    `json:"distanceMeters" binding:"gte=0"`
+	Duration       RaceTime `json:"duration"`
+	Effort         int32    `json:"effort" binding:"omitempty,min=1,max=10"`
+	Notes          string   `json:"notes"`
+}
+
+// WeeklyMileage totals the workouts in the week starting on WeekStart, a
+// Monday.
+type WeeklyMileage struct {
+	WeekStart string  `json:"weekStart"`
+	Workouts  int     `json:"workouts"`
+	Miles     float64 `json:"miles"`
+}
+
+// MileageResponse totals an athlete's training over a date range. From and
+// To are blank when the range is unbounded.
+type MileageResponse struct {
+	AthleteID          int32           `json:"athleteId"`
+	SeasonID           int32           `json:"seasonId,omitempty"`
+	From               string          `json:"from"`
+	To                 string          `json:"to"`
+	Workouts           int             `json:"workouts"`
+	TotalMiles         float64         `json:"totalMiles"`
+	AverageWeeklyMiles float64         `json:"averageWeeklyMiles"`
+	Weeks              []WeeklyMileage `json:"weeks"`
+}
+
+type AthleteMileage struct {
+	AthleteID          int32   `json:"athleteId"`
+	Name               string  `json:"name"`
+	Division           string  `json:"division"`
+	Workouts           int     `json:"workouts"`
+	Miles              float64 `json:"miles"`
+	AverageWeeklyMiles float64 `json:"averageWeeklyMiles"`
+}
+
+// TeamMileageResponse totals the whole team's training over a date range,
+// week by week and athlete by athlete, highest mileage first.
+type TeamMileageResponse struct {
+	SeasonID   int32            `json:"seasonId,omitempty"`
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	Workouts   int              `json:"workouts"`
+	TotalMiles float64          `json:"totalMiles"`
+	Weeks      []WeeklyMileage  `json:"weeks"`
+	Athletes   []AthleteMileage `json:"athletes"`
+}
+
+// mileageEntry is a workout reduced to what mileage totals need.
+type mileageEntry struct {
+	Date   time.Time
+	Meters int32
+}
+
+func newWorkoutResponse(w db.Workout) WorkoutResponse {
+	duration := RaceTime(w.DurationMs.Int32)
+	return WorkoutResponse{
+		ID:             w.ID,
+		AthleteID:      w.AthleteID,
+		Date:           w.Date.Format("2006-01-02"),
+		Type:           w.Type,
+		DistanceMeters: w.DistanceMeters,
+		Miles:          miles(int64(w.DistanceMeters)),
+		Duration:       duration,
+		PacePerMile:    duration.Pace(w.DistanceMeters, metersPerMile),
+		Effort:         w.Effort.Int32,
+		Notes:          w.Notes.String,
+	}
+}
+
+// miles converts a distance in meters to miles, rounded to two decimals.
+func miles(meters int64) float64 {
+	return math.Round(float64(meters)/metersPerMile*100) / 100
+}
+
+// weekStart returns the Monday of the week containing d.
+func weekStart(d time.Time) time.Time {
+	offset := (int(d.Weekday()) + 6) % 7
+	return time.Date(d.Year(), d.Month(), d.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// weeklyMileage totals entries by week over a date range, so weeks without
+// any workouts count at zero. An unbounded side of the range stops at the
+// first or last week with a workout, and a range running into the future
+// stops at the current week unless workouts are logged beyond it.
+func weeklyMileage(entries []mileageEntry, from, to time.Time) []WeeklyMileage {
+	var first, last time.Time
+	if !from.Equal(earliestDate) {
+		first = weekStart(from)
+	}
+	if !to.Equal(latestDate) {
+		end := to
+		if now := time.Now(); end.After(now) {
+			end = now
+		}
+		last = weekStart(end)
+	}
+	meters := make(map[time.Time]int64)
+	counts := make(map[time.Time]int)
+	for i, e := range entries {
+		week := weekStart(e.Date)
+		if from.Equal(earliestDate) && (i == 0 || week.Before(first)) {
+			first = week
+		}
+		if last.IsZero() || week.After(last) {
+			last = week
+		}
+		meters[week] += int64(e.Meters)
+		counts[week]++
+	}
+	if first.IsZero() || last.IsZero() {
+		return []WeeklyMileage{}
+	}
+
+	weeks := []WeeklyMileage{}
+	for week := first; !week.After(last); week = week.AddDate(0, 0, 7) {
+		weeks = append(weeks, WeeklyMileage{
+			WeekStart: week.Format("2006-01-02"),
+			Workouts:  counts[week],
+			Miles:     miles(meters[week]),
+		})
+	}
+	return weeks
+}
+
+// totalMiles adds up entries, converting to miles once to avoid rounding
+// drift.
+func totalMiles(entries []mileageEntry) float64 {
+	var meters int64
+	for _, e := range entries {
+		meters += int64(e.Meters)
+	}
+	return miles(meters)
+}
+
+func averageMiles(total float64, weeks int) float64 {
+	if weeks == 0 {
+		return 0
+	}
+	return math.Round(total/float64(weeks)*100) / 100
+}
+
+// mileageRange returns the dates selected by ?seasonId or ?from and ?to. With
+// neither it defaults to the current season, or to all time outside a season.
+func mileageRange(ctx context.Context, c *gin.Context) (time.Time, time.Time, int32, error) {
+	if s := c.Query("seasonId"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, badRequest("Invalid season ID")
+		}
+		season, err := queries.GetSeasonByID(ctx, int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return time.Time{}, time.Time{}, 0, badRequest("Season not found")
+			}
+			return time.Time{}, time.Time{}, 0, err
+		}
+		return season.StartDate, season.EndDate, season.ID, nil
+	}
+
+	if c.Query("from") != "" || c.Query("to") != "" {
+		from, err := dateQuery(c, "from", earliestDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, err
+		}
+		to, err := dateQuery(c, "to", latestDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, err
+		}
+		return from, to, 0, nil
+	}
+
+	today := time.Now()
+	season, err := queries.GetSeasonForDate(ctx, db.GetSeasonForDateParams{
+		StartDate: today,
+		EndDate:   today,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return earliestDate, latestDate, 0, nil
+		}
+		return time.Time{}, time.Time{}, 0, err
+	}
+	return season.StartDate, season.EndDate, season.ID, nil
+}
+
+// formatBound formats one end of a date range, leaving it blank when the range
+// is unbounded on that side.
+func formatBound(d time.Time) string {
+	if d.Equal(earliestDate) || d.Equal(latestDate) {
+		return ""
+	}
+	return d.Format("2006-01-02")
+}
+
+// validateWorkout parses a workout's date and checks it has something to log.
+func validateWorkout(req CreateWorkoutRequest) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return time.Time{}, badRequest("Invalid date format, use YYYY-MM-DD")
+	}
+	if req.DistanceMeters == 0 && req.Duration == 0 {
+		return time.Time{}, badRequest("A workout needs a distance or duration")
+	}
+	return date, nil
+}
+
+// workoutAthlete loads the athlete named in a workout route, reporting a
+// missing athlete as not found.
+func workoutAthlete(c *gin.Context) (db.Athlete, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid athlete ID"})
+		return db.Athlete{}, false
+	}
+	athlete, err := queries.GetAthleteByID(context.Background(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Athlete not found"})
+			return db.Athlete{}, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return db.Athlete{}, false
+	}
+	return athlete, true
+}
+
+func registerWorkoutRoutes(r *gin.Engine) {
+	// Get an athlete's workouts, newest first, optionally between ?from and ?to
+	r.GET("/api/athletes/:id/workouts", func(c *gin.Context) {
+		athlete, ok := workoutAthlete(c)
+		if !ok {
+			return
+		}
+
+		params := db.GetAthleteWorkoutsParams{AthleteID: athlete.ID}
+		var err error
+		if params.FromDate, err = dateQuery(c, "from", earliestDate); err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if params.ToDate, err = dateQuery(c, "to", latestDate); err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		workouts, err := queries.GetAthleteWorkouts(context.Background(), params)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]WorkoutResponse, len(workouts))
+		for i, w := range workouts {
+			response[i] = newWorkoutResponse(w)
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Log a workout for an athlete
+	r.POST("/api/athletes/:id/workouts", func(c *gin.Context) {
+		athlete, ok := workoutAthlete(c)
+		if !ok {
+			return
+		}
+
+		var req CreateWorkoutRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		date, err := validateWorkout(req)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := queries.CreateWorkout(context.Background(), db.CreateWorkoutParams{
+			AthleteID:      athlete.ID,
+			Date:           date,
+			Type:           req.Type,
+			DistanceMeters: req.DistanceMeters,
+			DurationMs:     sql.NullInt32{Int32: req.Duration.Milliseconds(), Valid: req.Duration > 0},
+			Effort:         sql.NullInt32{Int32: req.Effort, Valid: req.Effort > 0},
+			Notes:          sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		id, _ := result.LastInsertId()
+		c.JSON(http.StatusCreated, gin.H{"id": id, "message": "Workout created successfully"})
+	})
+
+	// Update one of an athlete's workouts
+	r.PUT("/api/athletes/:id/workouts/:workoutId", func(c *gin.Context) {
+		athlete, ok := workoutAthlete(c)
+		if !ok {
+			return
+		}
+		workoutID, err := strconv.Atoi(c.Param("workoutId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workout ID"})
+			return
+		}
+
+		var req CreateWorkoutRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		date, err := validateWorkout(req)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		_, err = queries.GetWorkout(context.Background(), db.GetWorkoutParams{
+			ID:        int32(workoutID),
+			AthleteID: athlete.ID,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Workout not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = queries.UpdateWorkout(context.Background(), db.UpdateWorkoutParams{
+			Date:           date,
+			Type:           req.Type,
+			DistanceMeters: req.DistanceMeters,
+			DurationMs:     sql.NullInt32{Int32: req.Duration.Milliseconds(), Valid: req.Duration > 0},
+			Effort:         sql.NullInt32{Int32: req.Effort, Valid: req.Effort > 0},
+			Notes:          sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+			ID:             int32(workoutID),
+			AthleteID:      athlete.ID,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Workout updated successfully"})
+	})
+
+	// Delete one of an athlete's workouts
+	r.DELETE("/api/athletes/:id/workouts/:workoutId", func(c *gin.Context) {
+		athleteID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid athlete ID"})
+			return
+		}
+		workoutID, err := strconv.Atoi(c.Param("workoutId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workout ID"})
+			return
+		}
+
+		err = queries.DeleteWorkout(context.Background(), db.DeleteWorkoutParams{
+			ID:        int32(workoutID),
+			AthleteID: int32(athleteID),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Workout deleted successfully"})
+	})
+
+	// Get an athlete's weekly and total mileage for ?seasonId, between ?from
+	// and ?to, or for the current season
+	r.GET("/api/athletes/:id/mileage", func(c *gin.Context) {
+		athlete, ok := workoutAthlete(c)
+		if !ok {
+			return
+		}
+
+		from, to, seasonID, err := mileageRange(context.Background(), c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		workouts, err := queries.GetAthleteWorkouts(context.Background(), db.GetAthleteWorkoutsParams{
+			AthleteID: athlete.ID,
+			FromDate:  from,
+			ToDate:    to,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		entries := make([]mileageEntry, len(workouts))
+		for i, w := range workouts {
+			entries[i] = mileageEntry{Date: w.Date, Meters: w.DistanceMeters}
+		}
+		weeks := weeklyMileage(entries, from, to)
+		total := totalMiles(entries)
+		c.JSON(http.StatusOK, MileageResponse{
+			AthleteID:          athlete.ID,
+			SeasonID:           seasonID,
+			From:               formatBound(from),
+			To:                 formatBound(to),
+			Workouts:           len(entries),
+			TotalMiles:         total,
+			AverageWeeklyMiles: averageMiles(total, len(weeks)),
+			Weeks:              weeks,
+		})
+	})
+
+	// Get the team's mileage week by week and per athlete for ?seasonId,
+	// between ?from and ?to, or for the current season
+	r.GET("/api/mileage", func(c *gin.Context) {
+		from, to, seasonID, err := mileageRange(context.Background(), c)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		workouts, err := queries.GetWorkoutsBetween(context.Background(), db.GetWorkoutsBetweenParams{
+			FromDate: from,
+			ToDate:   to,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		entries := make([]mileageEntry, len(workouts))
+		byAthlete := make(map[int32][]mileageEntry)
+		athletes := []AthleteMileage{}
+		for i, w := range workouts {
+			entries[i] = mileageEntry{Date: w.Date, Meters: w.DistanceMeters}
+			if _, ok := byAthlete[w.AthleteID]; !ok {
+				athletes = append(athletes, AthleteMileage{
+					AthleteID: w.AthleteID,
+					Name:      w.AthleteName,
+					Division:  w.AthleteDivision,
+				})
+			}
+			byAthlete[w.AthleteID] = append(byAthlete[w.AthleteID], entries[i])
+		}
+
+		weeks := weeklyMileage(entries, from, to)
+		for i := range athletes {
+			own := byAthlete[athletes[i].AthleteID]
+			athletes[i].Workouts = len(own)
+			athletes[i].Miles = totalMiles(own)
+			athletes[i].AverageWeeklyMiles = averageMiles(athletes[i].Miles, len(weeks))
+		}
+		sort.SliceStable(athletes, func(i, j int) bool {
+			if athletes[i].Miles != athletes[j].Miles {
+				return athletes[i].Miles > athletes[j].Miles
+			}
+			return athletes[i].Name < athletes[j].Name
+		})
+
+		c.JSON(http.StatusOK, TeamMileageResponse{
+			SeasonID:   seasonID,
+			From:       formatBound(from),
+			To:         formatBound(to),
+			Workouts:   len(entries),
+			TotalMiles: totalMiles(entries),
+			Weeks:      weeks,
+			Athletes:   athletes,
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func mustDate(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestWeeklyMileage(t *testing.T) {
+	entries := []mileageEntry{
+		{Date: mustDate("2024-09-11"), Meters: 8047},
+		{Date: mustDate("2024-09-12"), Meters: 8047},
+		{Date: mustDate("2024-09-25"), Meters: 16093},
+	}
+	tests := []struct {
+		name     string
+		entries  []mileageEntry
+		from, to time.Time
+		want     []WeeklyMileage
+	}{
+		{
+			name:    "unbounded",
+			entries: entries,
+			from:    earliestDate,
+			to:      latestDate,
+			want: []WeeklyMileage{
+				{WeekStart: "2024-09-09", Workouts: 2, Miles: 10},
+				{WeekStart: "2024-09-16", Workouts: 0, Miles: 0},
+				{WeekStart: "2024-09-23", Workouts: 1, Miles: 10},
+			},
+		},
+		{
+			name:    "bounded",
+			entries: entries,
+			from:    mustDate("2024-09-01"),
+			to:      mustDate("2024-10-01"),
+			want: []WeeklyMileage{
+				{WeekStart: "2024-08-26", Workouts: 0, Miles: 0},
+				{WeekStart: "2024-09-02", Workouts: 0, Miles: 0},
+				{WeekStart: "2024-09-09", Workouts: 2, Miles: 10},
+				{WeekStart: "2024-09-16", Workouts: 0, Miles: 0},
+				{WeekStart: "2024-09-23", Workouts: 1, Miles: 10},
+				{WeekStart: "2024-09-30", Workouts: 0, Miles: 0},
+			},
+		},
+		{
+			name:    "bounded without workouts",
+			entries: nil,
+			from:    mustDate("2024-09-02"),
+			to:      mustDate("2024-09-15"),
+			want: []WeeklyMileage{
+				{WeekStart: "2024-09-02", Workouts: 0, Miles: 0},
+				{WeekStart: "2024-09-09", Workouts: 0, Miles: 0},
+			},
+		},
+		{
+			name:    "unbounded without workouts",
+			entries: nil,
+			from:    earliestDate,
+			to:      latestDate,
+			want:    []WeeklyMileage{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := weeklyMileage(tt.entries, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d weeks %v, want %d %v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("week %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
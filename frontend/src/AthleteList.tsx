@@ -4,14 +4,24 @@ interface Athlete {
   id: number
   name: string
   grade: number
+  division: string
   personalRecord: string
   events: string
 }
 
+interface Page<T> {
+  data: T[]
+  total: number
+  page: number
+  limit: number
+  next: string | null
+}
+
 async function fetchAthletes(): Promise<Athlete[]> {
   const res = await fetch('/api/athletes')
   if (!res.ok) throw new Error('Failed to fetch athletes')
-  return res.json()
+  const page: Page<Athlete> = await res.json()
+  return page.data
 }
 
 export default function AthleteList() {